@@ -12,9 +12,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +31,10 @@ var (
 	s3Region    string
 	encrypt     bool
 	compress    bool
+	storageType string
+	endpointURL string
+	storeURL    string
+	autoBackup  bool
 )
 
 func init() {
@@ -41,13 +42,34 @@ func init() {
 	backupCmd.Flags().StringVar(&mongoURI, "mongo-uri", "", "MongoDB connection URI")
 	backupCmd.Flags().StringVar(&pgURI, "pg-uri", "", "PostgreSQL connection URI")
 	backupCmd.Flags().StringVar(&outputDir, "output", "/tmp/backups", "Output directory for backups")
-	backupCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "S3 bucket for backup storage")
-	backupCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "S3 region")
+	backupCmd.Flags().StringVar(&storeURL, "store", "", "URL-style storage target, e.g. s3://bucket/prefix, gs://bucket/prefix, azure://container/prefix, s3+minio://bucket/prefix, file:///local/dir, or sftp://user@host/dir (takes precedence over --s3-bucket)")
+	backupCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "Bucket/container for backup storage")
+	backupCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "Storage region (S3/MinIO)")
 	backupCmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt backup files")
 	backupCmd.Flags().BoolVar(&compress, "compress", true, "Compress backup files")
+	backupCmd.Flags().StringVar(&storageType, "storage", "s3", "Storage backend: s3, gcs, azure, or minio")
+	backupCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Custom endpoint URL (required for --storage=minio)")
+	backupCmd.Flags().MarkDeprecated("s3-bucket", "use --store instead, e.g. --store s3://bucket/prefix")
+	backupCmd.Flags().MarkDeprecated("storage", "use --store instead, e.g. --store gs://bucket/prefix")
+
+	// --auto turns this one-shot command into the same long-running loop
+	// the schedule subcommand drives, so these flags are bound to the
+	// package vars schedule.go already defines rather than duplicating them.
+	backupCmd.Flags().BoolVar(&autoBackup, "auto", false, "Keep running and perform a backup on --interval (or --cron) instead of exiting after one run")
+	backupCmd.Flags().DurationVar(&scheduleInterval, "interval", 0, "Interval between backups when --auto is set (e.g. 6h)")
+	backupCmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression for backups when --auto is set, alternative to --interval")
+	backupCmd.Flags().IntVar(&retentionDays, "retention-days", 0, "Delete backups older than N days after each successful run when --auto is set (0 disables)")
+	backupCmd.Flags().IntVar(&retentionCount, "retention-count", 0, "Keep only the N most recent backups after each successful run when --auto is set (0 disables)")
+	backupCmd.Flags().BoolVar(&vacuum, "vacuum", false, "Skip uploading a backup whose checksum matches the last successfully uploaded backup for that source (no-op backups)")
+	backupCmd.Flags().StringVar(&scheduleStateFile, "state-file", "", "File recording the last successful backup's timestamp/checksums, used by --vacuum and to avoid double-upload after a restart (default: <output>/.backup-state.json)")
 }
 
 func runBackup(cmd *cobra.Command, args []string) {
+	if autoBackup {
+		runSchedule(cmd, args)
+		return
+	}
+
 	timestamp := time.Now().Format("20060102-150405")
 	
 	// Ensure output directory exists
@@ -65,15 +87,19 @@ func runBackup(cmd *cobra.Command, args []string) {
 		if mongoURI == "" {
 			log.Fatal("MongoDB URI not provided (use --mongo-uri or MONGO_URI env var)")
 		}
-		
+
+		mongoStart := time.Now()
+		notifyBackupStart("mongo")
 		mongoPath, err := backupMongoDB(mongoURI, timestamp)
 		if err != nil {
+			notifyBackupResult("mongo", "", mongoStart, err)
 			log.Fatalf("MongoDB backup failed: %v", err)
 		}
+		notifyBackupResult("mongo", mongoPath, mongoStart, nil)
 		backupPaths = append(backupPaths, mongoPath)
 		fmt.Printf("MongoDB backup created: %s\n", mongoPath)
 	}
-	
+
 	// Backup PostgreSQL
 	if dbType == "postgres" || dbType == "both" {
 		if pgURI == "" {
@@ -82,22 +108,30 @@ func runBackup(cmd *cobra.Command, args []string) {
 		if pgURI == "" {
 			log.Fatal("PostgreSQL URI not provided (use --pg-uri or POSTGRES_URI env var)")
 		}
-		
+
+		pgStart := time.Now()
+		notifyBackupStart("postgres")
 		pgPath, err := backupPostgreSQL(pgURI, timestamp)
 		if err != nil {
+			notifyBackupResult("postgres", "", pgStart, err)
 			log.Fatalf("PostgreSQL backup failed: %v", err)
 		}
+		notifyBackupResult("postgres", pgPath, pgStart, nil)
 		backupPaths = append(backupPaths, pgPath)
 		fmt.Printf("PostgreSQL backup created: %s\n", pgPath)
 	}
 	
-	// Upload to S3 if configured
-	if s3Bucket != "" {
+	// Upload to remote storage if configured
+	if storageConfigured(storeURL, s3Bucket) {
+		backend, err := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
+		if err != nil {
+			log.Fatalf("Invalid storage configuration: %v", err)
+		}
 		for _, path := range backupPaths {
-			if err := uploadToS3(path, s3Bucket, s3Region); err != nil {
-				log.Printf("Failed to upload %s to S3: %v", path, err)
+			if err := uploadBackup(backend, path); err != nil {
+				log.Printf("Failed to upload %s: %v", path, err)
 			} else {
-				fmt.Printf("Backup uploaded to S3: s3://%s/%s\n", s3Bucket, filepath.Base(path))
+				fmt.Printf("Backup uploaded to %s: %s\n", storageDescription(storeURL, storageType), filepath.Base(path))
 			}
 		}
 	}
@@ -114,32 +148,75 @@ func backupMongoDB(uri, timestamp string) (string, error) {
 			dbName = strings.Split(parts[len(parts)-1], "?")[0]
 		}
 	}
-	
+
 	filename := fmt.Sprintf("mongo_%s_%s.archive", dbName, timestamp)
 	outputPath := filepath.Join(outputDir, filename)
-	
-	// Build mongodump command
+
+	// Build mongodump command. --oplog is always included so the range end
+	// recorded in the manifest reflects the oplog position at dump time,
+	// which a later incremental backup chains from.
 	args := []string{
 		"--uri", uri,
-		"--archive", outputPath,
+		"--oplog",
 	}
-	
+
 	if compress {
 		args = append(args, "--gzip")
 	}
-	
-	cmd := exec.Command("mongodump", args...)
-	cmd.Stderr = os.Stderr
-	
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("mongodump failed: %v", err)
+
+	var plainChecksum string
+
+	if encrypt {
+		// Stream mongodump's archive straight through AES-256-GCM into
+		// outputPath, via its stdout, so the plaintext dump never touches
+		// disk.
+		args = append(args, "--archive")
+		cmd := exec.Command("mongodump", args...)
+		cmd.Stderr = os.Stderr
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return "", fmt.Errorf("failed to open mongodump stdout: %v", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return "", fmt.Errorf("failed to start mongodump: %v", err)
+		}
+		plainChecksum, err = encryptStream(stdout, outputPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to stream-encrypt mongodump output: %v", err)
+		}
+		if err := cmd.Wait(); err != nil {
+			return "", fmt.Errorf("mongodump failed: %v", err)
+		}
+	} else {
+		args = append(args, "--archive", outputPath)
+		cmd := exec.Command("mongodump", args...)
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return "", fmt.Errorf("mongodump failed: %v", err)
+		}
 	}
-	
+
 	// Calculate and store checksum
 	if err := createChecksum(outputPath); err != nil {
 		log.Printf("Warning: Failed to create checksum for %s: %v", outputPath, err)
 	}
-	
+
+	rangeEnd, err := mongoOplogTimestamp(uri)
+	if err != nil {
+		log.Printf("Warning: Failed to capture oplog timestamp: %v", err)
+	}
+	schemaHash, err := computeSchemaHash("mongo", uri)
+	if err != nil {
+		log.Printf("Warning: Failed to compute schema hash: %v", err)
+	}
+	manifest, err := buildManifest("mongo", outputPath, "", rangeEnd, map[string]string{"mongodump": toolVersion("mongodump")}, compressionCodec(), plainChecksum, schemaHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to build manifest: %v", err)
+	}
+	if err := writeManifest(manifest); err != nil {
+		logManifestWarning(outputPath, err)
+	}
+
 	return outputPath, nil
 }
 
@@ -168,52 +245,114 @@ func backupPostgreSQL(uri, timestamp string) (string, error) {
 		"--create",
 	}
 	
-	cmd := exec.Command("pg_dump", args...)
-	
-	// Set up output redirection
-	outFile, err := os.Create(outputPath)
+	lsn, err := pgCurrentLSN(uri)
 	if err != nil {
-		return "", fmt.Errorf("failed to create output file: %v", err)
+		log.Printf("Warning: Failed to capture snapshot LSN: %v", err)
 	}
-	defer outFile.Close()
-	
-	if compress {
-		// Use gzip compression
-		gzipCmd := exec.Command("gzip")
-		gzipCmd.Stdin, _ = cmd.StdoutPipe()
-		gzipCmd.Stdout = outFile
-		gzipCmd.Stderr = os.Stderr
-		
-		if err := gzipCmd.Start(); err != nil {
-			return "", fmt.Errorf("failed to start gzip: %v", err)
-		}
-		
+
+	var plainChecksum string
+
+	if encrypt {
+		// Stream pg_dump's stdout (through gzip first, if --compress)
+		// straight through AES-256-GCM into outputPath, so the plaintext
+		// dump never touches disk.
+		cmd := exec.Command("pg_dump", args...)
 		cmd.Stderr = os.Stderr
+		dumpOut, err := cmd.StdoutPipe()
+		if err != nil {
+			return "", fmt.Errorf("failed to open pg_dump stdout: %v", err)
+		}
 		if err := cmd.Start(); err != nil {
 			return "", fmt.Errorf("failed to start pg_dump: %v", err)
 		}
-		
+
+		src := io.Reader(dumpOut)
+		var gzipCmd *exec.Cmd
+		if compress {
+			gzipCmd = exec.Command("gzip")
+			gzipCmd.Stdin = dumpOut
+			gzipCmd.Stderr = os.Stderr
+			gzOut, err := gzipCmd.StdoutPipe()
+			if err != nil {
+				return "", fmt.Errorf("failed to open gzip stdout: %v", err)
+			}
+			if err := gzipCmd.Start(); err != nil {
+				return "", fmt.Errorf("failed to start gzip: %v", err)
+			}
+			src = gzOut
+		}
+
+		plainChecksum, err = encryptStream(src, outputPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to stream-encrypt pg_dump output: %v", err)
+		}
 		if err := cmd.Wait(); err != nil {
 			return "", fmt.Errorf("pg_dump failed: %v", err)
 		}
-		
-		if err := gzipCmd.Wait(); err != nil {
-			return "", fmt.Errorf("gzip failed: %v", err)
+		if gzipCmd != nil {
+			if err := gzipCmd.Wait(); err != nil {
+				return "", fmt.Errorf("gzip failed: %v", err)
+			}
 		}
 	} else {
-		cmd.Stdout = outFile
-		cmd.Stderr = os.Stderr
-		
-		if err := cmd.Run(); err != nil {
-			return "", fmt.Errorf("pg_dump failed: %v", err)
+		cmd := exec.Command("pg_dump", args...)
+
+		outFile, err := os.Create(outputPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer outFile.Close()
+
+		if compress {
+			// Use gzip compression
+			gzipCmd := exec.Command("gzip")
+			gzipCmd.Stdin, _ = cmd.StdoutPipe()
+			gzipCmd.Stdout = outFile
+			gzipCmd.Stderr = os.Stderr
+
+			if err := gzipCmd.Start(); err != nil {
+				return "", fmt.Errorf("failed to start gzip: %v", err)
+			}
+
+			cmd.Stderr = os.Stderr
+			if err := cmd.Start(); err != nil {
+				return "", fmt.Errorf("failed to start pg_dump: %v", err)
+			}
+
+			if err := cmd.Wait(); err != nil {
+				return "", fmt.Errorf("pg_dump failed: %v", err)
+			}
+
+			if err := gzipCmd.Wait(); err != nil {
+				return "", fmt.Errorf("gzip failed: %v", err)
+			}
+		} else {
+			cmd.Stdout = outFile
+			cmd.Stderr = os.Stderr
+
+			if err := cmd.Run(); err != nil {
+				return "", fmt.Errorf("pg_dump failed: %v", err)
+			}
 		}
 	}
-	
+
 	// Calculate and store checksum
 	if err := createChecksum(outputPath); err != nil {
 		log.Printf("Warning: Failed to create checksum for %s: %v", outputPath, err)
 	}
-	
+
+	schemaHash, err := computeSchemaHash("postgres", uri)
+	if err != nil {
+		log.Printf("Warning: Failed to compute schema hash: %v", err)
+	}
+	manifest, err := buildManifest("postgres", outputPath, "", lsn, map[string]string{"pg_dump": toolVersion("pg_dump")}, compressionCodec(), plainChecksum, schemaHash)
+	if err != nil {
+		return "", fmt.Errorf("failed to build manifest: %v", err)
+	}
+	if err := writeManifest(manifest); err != nil {
+		logManifestWarning(outputPath, err)
+	}
+
 	return outputPath, nil
 }
 
@@ -235,41 +374,21 @@ func createChecksum(filePath string) error {
 	return os.WriteFile(checksumPath, []byte(checksum), 0644)
 }
 
-func uploadToS3(filePath, bucket, region string) error {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region),
-	})
-	if err != nil {
-		return fmt.Errorf("failed to create AWS session: %v", err)
-	}
-	
-	svc := s3.New(sess)
-	
-	file, err := os.Open(filePath)
-	if err != nil {
-		return fmt.Errorf("failed to open file: %v", err)
-	}
-	defer file.Close()
-	
+// uploadBackup uploads filePath (and its .sha256 sidecar, if present) to
+// backend under a key derived from the file's base name.
+func uploadBackup(backend StorageBackend, filePath string) error {
 	key := filepath.Base(filePath)
-	
-	_, err = svc.PutObjectWithContext(context.Background(), &s3.PutObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-		Body:   file,
-	})
-	
-	if err != nil {
-		return fmt.Errorf("failed to upload to S3: %v", err)
+
+	if err := backend.Upload(context.Background(), filePath, key); err != nil {
+		return err
 	}
-	
-	// Also upload checksum file
+
 	checksumPath := filePath + ".sha256"
 	if _, err := os.Stat(checksumPath); err == nil {
-		if err := uploadToS3(checksumPath, bucket, region); err != nil {
+		if err := backend.Upload(context.Background(), checksumPath, key+".sha256"); err != nil {
 			log.Printf("Warning: Failed to upload checksum file: %v", err)
 		}
 	}
-	
+
 	return nil
 }
\ No newline at end of file
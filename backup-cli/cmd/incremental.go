@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+var (
+	backupMode string // full, incremental, or differential
+	backupBase string // parent manifest.json an incremental/differential backup chains from
+)
+
+func init() {
+	backupCmd.Flags().StringVar(&backupMode, "mode", "full", "Backup mode: full, incremental, or differential")
+	backupCmd.Flags().StringVar(&backupBase, "base", "", "Parent manifest.json an incremental/differential backup chains from")
+	scheduleCmd.Flags().StringVar(&backupMode, "mode", "full", "Backup mode: full, incremental, or differential")
+	scheduleCmd.Flags().StringVar(&backupBase, "base", "", "Parent manifest.json an incremental/differential backup chains from")
+}
+
+// Manifest describes one backup in a full/incremental/differential chain,
+// uploaded alongside its archive so listCmd can reconstruct the lineage.
+type Manifest struct {
+	Mode              string            `json:"mode"`
+	DBType            string            `json:"db_type"`
+	ArchivePath       string            `json:"archive_path"`
+	CreatedAt         time.Time         `json:"created_at"`
+	ParentManifest    string            `json:"parent_manifest,omitempty"`
+	ParentChecksum    string            `json:"parent_checksum,omitempty"`
+	RangeStart        string            `json:"range_start,omitempty"` // LSN (postgres) or oplog timestamp (mongo)
+	RangeEnd          string            `json:"range_end,omitempty"`
+	ToolVersions      map[string]string `json:"tool_versions"`
+	CompressionCodec  string            `json:"compression_codec,omitempty"`
+	Encrypted         bool              `json:"encrypted"`
+	PlaintextChecksum string            `json:"plaintext_checksum,omitempty"` // SHA256 of the dump before encryption; the archive's own .sha256 sidecar covers the bytes actually at rest (ciphertext, when encrypted)
+	SchemaHash        string            `json:"schema_hash,omitempty"`        // SHA256 over the source's table/collection + column/index shape at backup time; compared against a --validate sandbox restore to catch silent schema drift
+}
+
+func manifestPathFor(archivePath string) string {
+	return archivePath + ".manifest.json"
+}
+
+func loadManifest(path string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return m, err
+	}
+	err = json.Unmarshal(data, &m)
+	return m, err
+}
+
+func writeManifest(m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	return os.WriteFile(manifestPathFor(m.ArchivePath), data, 0644)
+}
+
+// buildManifest assembles the manifest for a just-created archive, chaining
+// it to --base when the backup is incremental or differential.
+func buildManifest(dbType, archivePath, rangeStart, rangeEnd string, toolVersions map[string]string, compressionCodec, plaintextChecksum, schemaHash string) (Manifest, error) {
+	m := Manifest{
+		Mode:              backupMode,
+		DBType:            dbType,
+		ArchivePath:       archivePath,
+		CreatedAt:         time.Now(),
+		RangeStart:        rangeStart,
+		RangeEnd:          rangeEnd,
+		ToolVersions:      toolVersions,
+		CompressionCodec:  compressionCodec,
+		Encrypted:         encrypt,
+		PlaintextChecksum: plaintextChecksum,
+		SchemaHash:        schemaHash,
+	}
+
+	if backupMode == "incremental" || backupMode == "differential" {
+		if backupBase == "" {
+			return m, fmt.Errorf("--base is required for --mode=%s", backupMode)
+		}
+		parent, err := loadManifest(backupBase)
+		if err != nil {
+			return m, fmt.Errorf("failed to load parent manifest %s: %v", backupBase, err)
+		}
+		m.ParentManifest = backupBase
+		if m.RangeStart == "" {
+			m.RangeStart = parent.RangeEnd
+		}
+		checksum, err := os.ReadFile(parent.ArchivePath + ".sha256")
+		if err == nil {
+			m.ParentChecksum = strings.TrimSpace(string(checksum))
+		}
+	}
+
+	return m, nil
+}
+
+// compressionCodec reports which codec, if any, backupMongoDB/backupPostgreSQL
+// applied to the dump before --encrypt (if set) ran over it, for the
+// manifest's CompressionCodec field.
+func compressionCodec() string {
+	if compress {
+		return "gzip"
+	}
+	return "none"
+}
+
+func toolVersion(bin string, args ...string) string {
+	if len(args) == 0 {
+		args = []string{"--version"}
+	}
+	out, err := exec.Command(bin, args...).Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+}
+
+// pgCurrentLSN queries pg_current_wal_lsn() to snapshot the WAL position a
+// backup was taken at, so a later incremental knows where it picks up.
+func pgCurrentLSN(uri string) (string, error) {
+	out, err := exec.Command("psql", uri, "-t", "-A", "-c", "SELECT pg_current_wal_lsn();").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query pg_current_wal_lsn: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// mongoOplogTimestamp returns the timestamp at the top of the oplog, used as
+// the range end of an incremental Mongo backup chain.
+func mongoOplogTimestamp(uri string) (string, error) {
+	out, err := exec.Command("mongosh", uri, "--quiet", "--eval",
+		"db.getSiblingDB('local').oplog.rs.find().sort({$natural:-1}).limit(1).next().ts.toString()").Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to query oplog timestamp: %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// resolveChain walks a manifest's ParentManifest links back to the base full
+// backup and returns the manifests oldest-first, the order restoreCmd must
+// apply them in.
+func resolveChain(manifestPath string) ([]Manifest, error) {
+	var chain []Manifest
+	path := manifestPath
+	seen := make(map[string]bool)
+
+	for path != "" {
+		if seen[path] {
+			return nil, fmt.Errorf("broken backup lineage: cycle detected at %s", path)
+		}
+		seen[path] = true
+
+		m, err := loadManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load manifest %s: %v", path, err)
+		}
+		chain = append(chain, m)
+		path = m.ParentManifest
+	}
+
+	oldestFirst := make([]Manifest, len(chain))
+	for i, m := range chain {
+		oldestFirst[len(chain)-1-i] = m
+	}
+	return oldestFirst, nil
+}
+
+// trimChainToPointInTime drops every manifest created after target from the
+// end of an oldest-first chain, so a --point-in-time restore replays only
+// the backups that existed at that moment. It returns nil if even the base
+// backup postdates target.
+func trimChainToPointInTime(manifests []Manifest, target time.Time) []Manifest {
+	var trimmed []Manifest
+	for _, m := range manifests {
+		if m.CreatedAt.After(target) {
+			break
+		}
+		trimmed = append(trimmed, m)
+	}
+	return trimmed
+}
+
+func logManifestWarning(archivePath string, err error) {
+	log.Printf("Warning: Failed to write manifest for %s: %v", archivePath, err)
+}
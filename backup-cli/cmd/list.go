@@ -6,54 +6,56 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/spf13/cobra"
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List available backups",
-	Long:  `List available backups from local directory or S3 bucket`,
+	Long:  `List available backups from local directory or remote storage`,
 	Run:   runList,
 }
 
 var (
-	listLocal bool
-	listS3    bool
+	listLocal  bool
+	listRemote bool
 )
 
 func init() {
 	listCmd.Flags().BoolVar(&listLocal, "local", false, "List local backups")
-	listCmd.Flags().BoolVar(&listS3, "s3", false, "List S3 backups")
+	listCmd.Flags().BoolVar(&listRemote, "remote", false, "List backups in remote storage")
+	listCmd.Flags().BoolVar(&listRemote, "s3", false, "Alias for --remote (kept for backwards compatibility)")
 	listCmd.Flags().StringVar(&outputDir, "output", "/tmp/backups", "Local backup directory")
-	listCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "S3 bucket to list")
-	listCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "S3 region")
+	listCmd.Flags().StringVar(&storeURL, "store", "", "URL-style storage target, e.g. s3://bucket/prefix, gs://bucket/prefix, azure://container/prefix, s3+minio://bucket/prefix, file:///local/dir, or sftp://user@host/dir (takes precedence over --s3-bucket)")
+	listCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "Bucket/container to list")
+	listCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "Storage region (S3/MinIO)")
+	listCmd.Flags().StringVar(&storageType, "storage", "s3", "Storage backend: s3, gcs, azure, or minio")
+	listCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Custom endpoint URL (required for --storage=minio)")
+	listCmd.Flags().MarkDeprecated("s3-bucket", "use --store instead, e.g. --store s3://bucket/prefix")
+	listCmd.Flags().MarkDeprecated("storage", "use --store instead, e.g. --store gs://bucket/prefix")
 }
 
 func runList(cmd *cobra.Command, args []string) {
-	if !listLocal && !listS3 {
+	if !listLocal && !listRemote {
 		// Default to both if neither specified
 		listLocal = true
-		listS3 = s3Bucket != ""
+		listRemote = storageConfigured(storeURL, s3Bucket)
 	}
-	
+
 	if listLocal {
 		fmt.Println("=== Local Backups ===")
 		listLocalBackups()
 		fmt.Println()
 	}
-	
-	if listS3 {
-		if s3Bucket == "" {
-			fmt.Println("S3 bucket not specified, skipping S3 listing")
+
+	if listRemote {
+		if !storageConfigured(storeURL, s3Bucket) {
+			fmt.Println("Store not specified, skipping remote listing")
 			return
 		}
-		fmt.Println("=== S3 Backups ===")
-		listS3Backups()
+		fmt.Printf("=== Remote Backups (%s) ===\n", storageDescription(storeURL, storageType))
+		listRemoteBackups()
 	}
 }
 
@@ -111,74 +113,60 @@ func listLocalBackups() {
 	}
 }
 
-func listS3Backups() {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(s3Region),
-	})
+func listRemoteBackups() {
+	backend, err := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
 	if err != nil {
-		fmt.Printf("Failed to create AWS session: %v\n", err)
+		fmt.Printf("Invalid storage configuration: %v\n", err)
 		return
 	}
-	
-	svc := s3.New(sess)
-	
-	input := &s3.ListObjectsV2Input{
-		Bucket: aws.String(s3Bucket),
-	}
-	
-	result, err := svc.ListObjectsV2WithContext(context.Background(), input)
+
+	objects, err := backend.List(context.Background(), "")
 	if err != nil {
-		fmt.Printf("Failed to list S3 objects: %v\n", err)
+		fmt.Printf("Failed to list remote objects: %v\n", err)
 		return
 	}
-	
+
 	// Filter backup files (exclude checksums for main listing)
-	var backupFiles []*s3.Object
-	for _, obj := range result.Contents {
-		if !strings.HasSuffix(*obj.Key, ".sha256") {
-			// Check if it looks like a backup file
-			key := *obj.Key
-			if strings.Contains(key, "mongo") || strings.Contains(key, "postgres") ||
-				strings.HasSuffix(key, ".archive") || strings.HasSuffix(key, ".sql") ||
-				strings.HasSuffix(key, ".sql.gz") {
-				backupFiles = append(backupFiles, obj)
-			}
+	var backupFiles []StorageObject
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, ".sha256") {
+			continue
+		}
+		if strings.Contains(obj.Key, "mongo") || strings.Contains(obj.Key, "postgres") ||
+			strings.HasSuffix(obj.Key, ".archive") || strings.HasSuffix(obj.Key, ".sql") ||
+			strings.HasSuffix(obj.Key, ".sql.gz") {
+			backupFiles = append(backupFiles, obj)
 		}
 	}
-	
+
 	if len(backupFiles) == 0 {
-		fmt.Printf("No backup files found in s3://%s\n", s3Bucket)
+		fmt.Printf("No backup files found in %s\n", storageDescription(storeURL, storageType))
 		return
 	}
-	
-	fmt.Printf("Found %d backup files in s3://%s:\n\n", len(backupFiles), s3Bucket)
-	
+
+	fmt.Printf("Found %d backup files in %s:\n\n", len(backupFiles), storageDescription(storeURL, storageType))
+
 	for _, obj := range backupFiles {
-		// Determine database type
-		key := *obj.Key
 		dbType := "Unknown"
-		if strings.Contains(key, "mongo") {
+		if strings.Contains(obj.Key, "mongo") {
 			dbType = "MongoDB"
-		} else if strings.Contains(key, "postgres") {
+		} else if strings.Contains(obj.Key, "postgres") {
 			dbType = "PostgreSQL"
 		}
-		
-		// Check for checksum file
+
 		checksumExists := "No"
-		checksumKey := key + ".sha256"
-		for _, checkObj := range result.Contents {
-			if *checkObj.Key == checksumKey {
-				checksumExists = "Yes"
-				break
-			}
+		if backend.HasSidecar(context.Background(), obj.Key) {
+			checksumExists = "Yes"
 		}
-		
-		fmt.Printf("Key: %s\n", key)
+
+		fmt.Printf("Key: %s\n", obj.Key)
 		fmt.Printf("  Type: %s\n", dbType)
-		fmt.Printf("  Size: %s\n", formatFileSize(*obj.Size))
+		fmt.Printf("  Size: %s\n", formatFileSize(obj.Size))
 		fmt.Printf("  Modified: %s\n", obj.LastModified.Format("2006-01-02 15:04:05"))
 		fmt.Printf("  Checksum: %s\n", checksumExists)
-		fmt.Printf("  Storage Class: %s\n", aws.StringValue(obj.StorageClass))
+		if obj.StorageClass != "" {
+			fmt.Printf("  Storage Class: %s\n", obj.StorageClass)
+		}
 		fmt.Println()
 	}
 }
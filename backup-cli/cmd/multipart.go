@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// minMultipartPartSize is the smallest part size S3 accepts for every part
+// but the last.
+const minMultipartPartSize = 5 * 1024 * 1024
+
+var (
+	uploadConcurrency  int
+	uploadPartSizeMB   int64
+	rateLimitMBs       float64
+	resumeUpload       bool
+	sseMode            string
+	sseKMSKeyID        string
+	uploadStorageClass string
+	uploadACL          string
+)
+
+func init() {
+	backupCmd.Flags().IntVar(&uploadConcurrency, "upload-concurrency", 4, "Number of multipart upload parts to send in parallel")
+	backupCmd.Flags().Int64Var(&uploadPartSizeMB, "upload-part-size", 16, "Multipart upload part size in MiB")
+	backupCmd.Flags().Float64Var(&rateLimitMBs, "ratelimit", 0, "Upload rate limit in MB/s per part (0 disables)")
+	backupCmd.Flags().BoolVar(&resumeUpload, "resume", false, "Resume an interrupted multipart upload from its .upload-state.json")
+	backupCmd.Flags().StringVar(&sseMode, "sse", "", "Server-side encryption: AES256 or aws:kms")
+	backupCmd.Flags().StringVar(&sseKMSKeyID, "sse-kms-key-id", "", "KMS key ID for --sse=aws:kms")
+	backupCmd.Flags().StringVar(&uploadStorageClass, "storage-class", "", "S3 storage class: STANDARD_IA, GLACIER, DEEP_ARCHIVE, ...")
+	backupCmd.Flags().StringVar(&uploadACL, "acl", "", "S3 canned ACL to apply to uploaded objects")
+}
+
+// uploadState is checkpointed to "<localPath>.upload-state.json" after every
+// completed part so an interrupted multipart upload can resume via --resume
+// instead of restarting from byte zero.
+type uploadState struct {
+	Key      string                 `json:"key"`
+	UploadID string                 `json:"upload_id"`
+	PartSize int64                  `json:"part_size"`
+	Parts    []completedUploadPart  `json:"parts"`
+}
+
+type completedUploadPart struct {
+	PartNumber int64  `json:"part_number"`
+	ETag       string `json:"etag"`
+}
+
+func uploadStatePath(localPath string) string {
+	return localPath + ".upload-state.json"
+}
+
+func loadUploadState(localPath, key string, partSize int64) *uploadState {
+	data, err := os.ReadFile(uploadStatePath(localPath))
+	if err != nil {
+		return nil
+	}
+	var st uploadState
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil
+	}
+	if st.Key != key || st.PartSize != partSize {
+		return nil // state belongs to a different upload attempt; start fresh
+	}
+	return &st
+}
+
+func saveUploadState(localPath string, st *uploadState) {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return // best effort; a failed checkpoint just means a redundant part on resume
+	}
+	os.WriteFile(uploadStatePath(localPath), data, 0644)
+}
+
+// multipartUpload uploads localPath to key in uploadConcurrency-wide
+// parallel part requests, checkpointing progress so --resume can pick up
+// an interrupted transfer without re-sending completed parts.
+func (b *s3Backend) multipartUpload(ctx context.Context, svc *s3.S3, localPath, key string, size, partSize int64) error {
+	statePath := uploadStatePath(localPath)
+
+	var st *uploadState
+	if resumeUpload {
+		st = loadUploadState(localPath, key, partSize)
+		if st != nil {
+			fmt.Printf("Resuming multipart upload %s (%d parts already completed)\n", st.UploadID, len(st.Parts))
+		}
+	}
+
+	if st == nil {
+		createInput := &s3.CreateMultipartUploadInput{
+			Bucket: aws.String(b.bucket),
+			Key:    aws.String(key),
+		}
+		if sseMode != "" {
+			createInput.ServerSideEncryption = aws.String(sseMode)
+			if sseKMSKeyID != "" {
+				createInput.SSEKMSKeyId = aws.String(sseKMSKeyID)
+			}
+		}
+		if uploadStorageClass != "" {
+			createInput.StorageClass = aws.String(uploadStorageClass)
+		}
+		if uploadACL != "" {
+			createInput.ACL = aws.String(uploadACL)
+		}
+		out, err := svc.CreateMultipartUploadWithContext(ctx, createInput)
+		if err != nil {
+			return fmt.Errorf("failed to start multipart upload to %s: %v", b.describe(), err)
+		}
+		st = &uploadState{Key: key, UploadID: aws.StringValue(out.UploadId), PartSize: partSize}
+	}
+
+	done := make(map[int64]bool, len(st.Parts))
+	for _, p := range st.Parts {
+		done[p.PartNumber] = true
+	}
+
+	totalParts := (size + partSize - 1) / partSize
+
+	var limiter *rateLimiter
+	if rateLimitMBs > 0 {
+		limiter = newRateLimiter(rateLimitMBs * 1024 * 1024)
+	}
+
+	var (
+		mu       sync.Mutex
+		stateMu  sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, uploadConcurrency)
+	)
+
+	for partNum := int64(1); partNum <= totalParts; partNum++ {
+		if done[partNum] {
+			continue
+		}
+
+		mu.Lock()
+		abort := firstErr != nil
+		mu.Unlock()
+		if abort {
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(partNum int64) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			offset := (partNum - 1) * partSize
+			length := partSize
+			if offset+length > size {
+				length = size - offset
+			}
+
+			file, err := os.Open(localPath)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			defer file.Close()
+
+			var reader io.Reader = io.NewSectionReader(file, offset, length)
+			if limiter != nil {
+				reader = limiter.wrap(reader)
+			}
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to read part %d: %v", partNum, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			out, err := svc.UploadPartWithContext(ctx, &s3.UploadPartInput{
+				Bucket:     aws.String(b.bucket),
+				Key:        aws.String(key),
+				UploadId:   aws.String(st.UploadID),
+				PartNumber: aws.Int64(partNum),
+				Body:       bytes.NewReader(data),
+			})
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("failed to upload part %d: %v", partNum, err)
+				}
+				mu.Unlock()
+				return
+			}
+
+			stateMu.Lock()
+			st.Parts = append(st.Parts, completedUploadPart{PartNumber: partNum, ETag: aws.StringValue(out.ETag)})
+			saveUploadState(localPath, st)
+			stateMu.Unlock()
+		}(partNum)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return fmt.Errorf("multipart upload to %s failed, state saved for --resume: %v", b.describe(), firstErr)
+	}
+
+	sort.Slice(st.Parts, func(i, j int) bool { return st.Parts[i].PartNumber < st.Parts[j].PartNumber })
+	completedParts := make([]*s3.CompletedPart, len(st.Parts))
+	for i, p := range st.Parts {
+		completedParts[i] = &s3.CompletedPart{PartNumber: aws.Int64(p.PartNumber), ETag: aws.String(p.ETag)}
+	}
+
+	_, err := svc.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(b.bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(st.UploadID),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completedParts},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload to %s: %v", b.describe(), err)
+	}
+
+	os.Remove(statePath)
+	return nil
+}
+
+// rateLimiter is a minimal token-bucket throttle applied per upload part to
+// approximate --ratelimit MB/s; it sleeps proportionally to bytes read
+// rather than tracking a true bucket, which is sufficient for the
+// coarse-grained throttling this flag is meant to provide.
+type rateLimiter struct {
+	bytesPerSec float64
+	mu          sync.Mutex
+}
+
+func newRateLimiter(bytesPerSec float64) *rateLimiter {
+	return &rateLimiter{bytesPerSec: bytesPerSec}
+}
+
+func (r *rateLimiter) wrap(reader io.Reader) io.Reader {
+	return &rateLimitedReader{r: reader, limiter: r}
+}
+
+func (r *rateLimiter) wait(n int) {
+	if r.bytesPerSec <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	time.Sleep(time.Duration(float64(n) / r.bytesPerSec * float64(time.Second)))
+}
+
+type rateLimitedReader struct {
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (rr *rateLimitedReader) Read(p []byte) (int, error) {
+	n, err := rr.r.Read(p)
+	if n > 0 {
+		rr.limiter.wait(n)
+	}
+	return n, err
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"compress/gzip"
 	"context"
 	"crypto/sha256"
 	"fmt"
@@ -10,10 +11,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/spf13/cobra"
 )
 
@@ -31,6 +30,8 @@ var (
 	targetMongoURI  string
 	targetPgURI     string
 	skipVerify      bool
+	pointInTime     string
+	restoreDryRun   bool
 )
 
 func init() {
@@ -39,26 +40,37 @@ func init() {
 	restoreCmd.Flags().StringVar(&restoreDbType, "db", "", "Database type: mongo or postgres (auto-detected if not specified)")
 	restoreCmd.Flags().StringVar(&targetMongoURI, "mongo-uri", "", "Target MongoDB URI for restore")
 	restoreCmd.Flags().StringVar(&targetPgURI, "pg-uri", "", "Target PostgreSQL URI for restore")
-	restoreCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "S3 bucket containing backup")
-	restoreCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "S3 region")
+	restoreCmd.Flags().StringVar(&storeURL, "store", "", "URL-style storage target, e.g. s3://bucket/prefix, gs://bucket/prefix, azure://container/prefix, s3+minio://bucket/prefix, file:///local/dir, or sftp://user@host/dir (takes precedence over --s3-bucket)")
+	restoreCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "Bucket/container containing the backup")
+	restoreCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "Storage region (S3/MinIO)")
 	restoreCmd.Flags().BoolVar(&skipVerify, "skip-verify", false, "Skip backup verification before restore")
+	restoreCmd.Flags().StringVar(&storageType, "storage", "s3", "Storage backend: s3, gcs, azure, or minio")
+	restoreCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Custom endpoint URL (required for --storage=minio)")
+	restoreCmd.Flags().MarkDeprecated("s3-bucket", "use --store instead, e.g. --store s3://bucket/prefix")
+	restoreCmd.Flags().MarkDeprecated("storage", "use --store instead, e.g. --store gs://bucket/prefix")
+	restoreCmd.Flags().StringVar(&pointInTime, "point-in-time", "", "RFC3339 timestamp to restore up to, replaying only the manifest chain entries created at or before it (requires --file/--s3-key to name the latest backup in the chain)")
+	restoreCmd.Flags().BoolVar(&restoreDryRun, "dry-run", false, "Print the resolved restore plan (base + incremental chain) without executing it")
 }
 
 func runRestore(cmd *cobra.Command, args []string) {
 	var filePath string
 	var err error
 	
-	// Download from S3 if S3 key is provided
+	// Download from remote storage if a key is provided
 	if restoreS3Key != "" {
-		if s3Bucket == "" {
-			log.Fatal("S3 bucket must be specified when using S3 key")
+		if !storageConfigured(storeURL, s3Bucket) {
+			log.Fatal("--store (or --s3-bucket) must be specified when using --s3-key")
 		}
-		filePath, err = downloadFromS3(restoreS3Key, s3Bucket, s3Region)
+		backend, err2 := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
+		if err2 != nil {
+			log.Fatalf("Invalid storage configuration: %v", err2)
+		}
+		filePath, err = downloadBackup(backend, restoreS3Key)
 		if err != nil {
-			log.Fatalf("Failed to download from S3: %v", err)
+			log.Fatalf("Failed to download from %s: %v", storageDescription(storeURL, storageType), err)
 		}
 		defer os.Remove(filePath) // Clean up downloaded file
-		fmt.Printf("Downloaded backup from S3: %s\n", restoreS3Key)
+		fmt.Printf("Downloaded backup from %s: %s\n", storageDescription(storeURL, storageType), restoreS3Key)
 	} else if restoreFile != "" {
 		filePath = restoreFile
 	} else {
@@ -72,7 +84,7 @@ func runRestore(cmd *cobra.Command, args []string) {
 		}
 		fmt.Println("Backup verification successful")
 	}
-	
+
 	// Auto-detect database type if not specified
 	if restoreDbType == "" {
 		restoreDbType = detectDatabaseType(filePath)
@@ -81,71 +93,166 @@ func runRestore(cmd *cobra.Command, args []string) {
 		}
 	}
 	
-	// Perform restore based on database type
-	switch restoreDbType {
-	case "mongo":
-		if err := restoreMongoDB(filePath); err != nil {
-			log.Fatalf("MongoDB restore failed: %v", err)
+	// Replay the full backup followed by any chained incrementals/differentials
+	// when a manifest is present, otherwise restore filePath on its own.
+	var chainManifests []Manifest
+	if manifestPath := manifestPathFor(filePath); fileExists(manifestPath) {
+		resolved, err := resolveChain(manifestPath)
+		if err != nil {
+			log.Fatalf("Failed to resolve backup chain: %v", err)
+		}
+		chainManifests = resolved
+	}
+
+	var target time.Time
+	if pointInTime != "" {
+		var err error
+		target, err = time.Parse(time.RFC3339, pointInTime)
+		if err != nil {
+			log.Fatalf("Invalid --point-in-time %q: %v", pointInTime, err)
+		}
+		if len(chainManifests) == 0 {
+			log.Fatalf("--point-in-time requires a backup chain manifest; none found for %s", filePath)
 		}
-		fmt.Println("MongoDB restore completed successfully")
-	case "postgres":
-		if err := restorePostgreSQL(filePath); err != nil {
-			log.Fatalf("PostgreSQL restore failed: %v", err)
+		chainManifests = trimChainToPointInTime(chainManifests, target)
+		if len(chainManifests) == 0 {
+			log.Fatalf("No backup in the chain was taken at or before %s", pointInTime)
+		}
+		last := chainManifests[len(chainManifests)-1]
+		switch last.DBType {
+		case "postgres":
+			log.Printf("Warning: postgres backups here are logical pg_dump snapshots, not base backups with continuously archived WAL, so --point-in-time can only land on a backup boundary, not an arbitrary instant within it")
+		case "mongo":
+			// Each mongo backup is a standalone mongodump --oplog run: the
+			// oplog it embeds only spans that one dump's own duration, never
+			// continuously across backups, so --oplogLimit can trim replay
+			// back to an earlier instant inside this backup but can't reach
+			// forward into the gap before the next one.
+			if target.After(last.CreatedAt) {
+				log.Fatalf("--point-in-time %s is not reachable: mongo backups here aren't continuously archived between runs, so the only instant reachable from this chain is this backup's own completion boundary (%s) or earlier, not an arbitrary later instant", pointInTime, last.CreatedAt.Format(time.RFC3339))
+			}
+		}
+	}
+
+	chain := []string{filePath}
+	if len(chainManifests) > 0 {
+		chain = make([]string, len(chainManifests))
+		for i, m := range chainManifests {
+			chain[i] = m.ArchivePath
 		}
-		fmt.Println("PostgreSQL restore completed successfully")
-	default:
-		log.Fatalf("Unsupported database type: %s", restoreDbType)
 	}
+
+	if len(chain) > 1 {
+		fmt.Printf("Restoring chain of %d backups\n", len(chain))
+	}
+
+	if restoreDryRun {
+		fmt.Println("Restore plan:")
+		for i, archivePath := range chain {
+			if i < len(chainManifests) {
+				m := chainManifests[i]
+				fmt.Printf("  %d. [%s/%s] %s (created %s, range %s..%s)\n",
+					i+1, m.DBType, m.Mode, archivePath, m.CreatedAt.Format(time.RFC3339), m.RangeStart, m.RangeEnd)
+			} else {
+				fmt.Printf("  %d. %s\n", i+1, archivePath)
+			}
+		}
+		return
+	}
+
+	if validateMode {
+		if err := runValidate(restoreDbType, chain, chainManifests); err != nil {
+			log.Fatalf("Validation failed: %v", err)
+		}
+		fmt.Println("Validation succeeded")
+		return
+	}
+
+	// Perform restore based on database type. Each archive in the chain is
+	// decrypted on the fly, streamed directly into mongorestore/psql's
+	// stdin, so the plaintext dump never touches disk here.
+	restoreStart := time.Now()
+	notifyBackupStart(restoreDbType)
+
+	for i, archivePath := range chain {
+		encrypted := isEncryptedBackup(archivePath)
+
+		// Only the last archive applied needs to stop replay at the target
+		// instant; every earlier one in the chain is replayed in full.
+		var oplogLimit time.Time
+		if pointInTime != "" && i == len(chain)-1 {
+			oplogLimit = target
+		}
+
+		switch restoreDbType {
+		case "mongo":
+			if err := restoreMongoDB(archivePath, encrypted, oplogLimit); err != nil {
+				notifyBackupResult(restoreDbType, archivePath, restoreStart, err)
+				log.Fatalf("MongoDB restore failed: %v", err)
+			}
+			fmt.Printf("MongoDB restore completed successfully: %s\n", archivePath)
+		case "postgres":
+			if err := restorePostgreSQL(archivePath, encrypted); err != nil {
+				notifyBackupResult(restoreDbType, archivePath, restoreStart, err)
+				log.Fatalf("PostgreSQL restore failed: %v", err)
+			}
+			fmt.Printf("PostgreSQL restore completed successfully: %s\n", archivePath)
+		default:
+			notifyBackupResult(restoreDbType, "", restoreStart, fmt.Errorf("unsupported database type: %s", restoreDbType))
+			log.Fatalf("Unsupported database type: %s", restoreDbType)
+		}
+	}
+
+	notifyBackupResult(restoreDbType, chain[len(chain)-1], restoreStart, nil)
 }
 
-func downloadFromS3(key, bucket, region string) (string, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(region),
-	})
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// archiveSize returns filePath's on-disk size for use as a progress total
+// (the decrypted/decompressed stream is roughly the same order of
+// magnitude), or 0 if it can't be statted.
+func archiveSize(filePath string) int64 {
+	info, err := os.Stat(filePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to create AWS session: %v", err)
+		return 0
 	}
-	
-	svc := s3.New(sess)
-	
-	// Create temporary file
+	return info.Size()
+}
+
+// downloadBackup downloads key from backend into a new temporary file and
+// returns its path.
+func downloadBackup(backend StorageBackend, key string) (string, error) {
 	tmpFile, err := os.CreateTemp("", "backup-restore-*")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temporary file: %v", err)
 	}
-	defer tmpFile.Close()
-	
-	// Download from S3
-	result, err := svc.GetObjectWithContext(context.Background(), &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-	})
-	if err != nil {
-		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("failed to download from S3: %v", err)
-	}
-	defer result.Body.Close()
-	
-	// Copy to temporary file
-	if _, err := io.Copy(tmpFile, result.Body); err != nil {
+	tmpFile.Close()
+
+	if err := backend.Download(context.Background(), key, tmpFile.Name()); err != nil {
 		os.Remove(tmpFile.Name())
-		return "", fmt.Errorf("failed to write downloaded data: %v", err)
+		return "", err
 	}
-	
+
 	return tmpFile.Name(), nil
 }
 
 func verifyBackup(filePath string) error {
 	checksumPath := filePath + ".sha256"
-	
+
 	// Check if checksum file exists
 	if _, err := os.Stat(checksumPath); os.IsNotExist(err) {
-		// If S3 key was used, try to download checksum file
-		if restoreS3Key != "" && s3Bucket != "" {
-			checksumKey := restoreS3Key + ".sha256"
-			downloadedChecksum, err := downloadFromS3(checksumKey, s3Bucket, s3Region)
+		// If a remote key was used, try to download the checksum sidecar
+		if restoreS3Key != "" && storageConfigured(storeURL, s3Bucket) {
+			backend, err := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
+			if err != nil {
+				return fmt.Errorf("invalid storage configuration: %v", err)
+			}
+			downloadedChecksum, err := downloadBackup(backend, restoreS3Key+".sha256")
 			if err != nil {
-				return fmt.Errorf("checksum file not found and could not download from S3: %v", err)
+				return fmt.Errorf("checksum file not found and could not download it: %v", err)
 			}
 			checksumPath = downloadedChecksum
 			defer os.Remove(downloadedChecksum)
@@ -193,68 +300,145 @@ func detectDatabaseType(filePath string) string {
 	return ""
 }
 
-func restoreMongoDB(filePath string) error {
+func restoreMongoDB(filePath string, encrypted bool, oplogLimit time.Time) error {
 	if targetMongoURI == "" {
 		targetMongoURI = os.Getenv("MONGO_URI")
 	}
 	if targetMongoURI == "" {
 		return fmt.Errorf("MongoDB URI not provided (use --mongo-uri or MONGO_URI env var)")
 	}
-	
-	// Build mongorestore command
+
 	args := []string{
 		"--uri", targetMongoURI,
-		"--archive", filePath,
 		"--drop", // Drop collections before restoring
 	}
-	
-	// Check if file is gzipped
+
+	// Check if the dump is gzipped
 	if strings.HasSuffix(filePath, ".gz") || strings.Contains(filePath, ".archive") {
 		args = append(args, "--gzip")
 	}
-	
+
+	if !oplogLimit.IsZero() {
+		// oplogReplay applies the archive's embedded oplog slice (captured
+		// via mongodump --oplog) up to, but not past, oplogLimit. That only
+		// gives second-granularity precision inside this one dump's own
+		// capture window; runRestore rejects any --point-in-time target
+		// past this backup's own completion, since the oplog isn't
+		// continuously archived across backups.
+		args = append(args, "--oplogReplay", "--oplogLimit", fmt.Sprintf("%d:0", oplogLimit.Unix()))
+	}
+
+	if encrypted {
+		// Decrypt straight into mongorestore's stdin ("--archive" with no
+		// value means read the archive from stdin), so the plaintext dump
+		// never touches disk.
+		args = append(args, "--archive")
+		cmd := exec.Command("mongorestore", args...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("failed to open mongorestore stdin: %v", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start mongorestore: %v", err)
+		}
+
+		progressStdin := wrapProgressWriter(stdin, filepath.Base(filePath), archiveSize(filePath))
+		decErr := decryptStream(filePath, progressStdin)
+		progressStdin.Close()
+		if waitErr := cmd.Wait(); waitErr != nil {
+			return fmt.Errorf("mongorestore failed: %v", waitErr)
+		}
+		return decErr
+	}
+
+	args = append(args, "--archive", filePath)
 	cmd := exec.Command("mongorestore", args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	
+
 	return cmd.Run()
 }
 
-func restorePostgreSQL(filePath string) error {
+func restorePostgreSQL(filePath string, encrypted bool) error {
 	if targetPgURI == "" {
 		targetPgURI = os.Getenv("POSTGRES_URI")
 	}
 	if targetPgURI == "" {
 		return fmt.Errorf("PostgreSQL URI not provided (use --pg-uri or POSTGRES_URI env var)")
 	}
-	
-	var cmd *exec.Cmd
-	
+
+	gzipped := strings.HasSuffix(filePath, ".gz")
+
+	if encrypted {
+		// Decrypt straight into psql's stdin, gunzipping first if the dump
+		// was compressed, so the plaintext dump never touches disk.
+		psqlCmd := exec.Command("psql", targetPgURI)
+		psqlCmd.Stdout = os.Stdout
+		psqlCmd.Stderr = os.Stderr
+
+		stdin, err := psqlCmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("failed to open psql stdin: %v", err)
+		}
+		if err := psqlCmd.Start(); err != nil {
+			return fmt.Errorf("failed to start psql: %v", err)
+		}
+
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(decryptStream(filePath, pw))
+		}()
+
+		src := io.Reader(pr)
+		if gzipped {
+			gz, err := gzip.NewReader(pr)
+			if err != nil {
+				stdin.Close()
+				psqlCmd.Wait()
+				return fmt.Errorf("failed to open gzip stream: %v", err)
+			}
+			defer gz.Close()
+			src = gz
+		}
+		src = wrapProgressReader(src, filepath.Base(filePath), 0)
+
+		_, copyErr := io.Copy(stdin, src)
+		stdin.Close()
+		waitErr := psqlCmd.Wait()
+		if copyErr != nil {
+			return fmt.Errorf("failed to stream decrypted backup: %v", copyErr)
+		}
+		return waitErr
+	}
+
 	// Handle compressed files
-	if strings.HasSuffix(filePath, ".gz") {
+	if gzipped {
 		// Use zcat to decompress and pipe to psql
 		zcatCmd := exec.Command("zcat", filePath)
 		psqlCmd := exec.Command("psql", targetPgURI)
-		
+
 		psqlCmd.Stdin, _ = zcatCmd.StdoutPipe()
 		psqlCmd.Stdout = os.Stdout
 		psqlCmd.Stderr = os.Stderr
-		
+
 		if err := psqlCmd.Start(); err != nil {
 			return fmt.Errorf("failed to start psql: %v", err)
 		}
-		
+
 		if err := zcatCmd.Run(); err != nil {
 			return fmt.Errorf("failed to decompress backup: %v", err)
 		}
-		
+
 		return psqlCmd.Wait()
-	} else {
-		// Direct restore from uncompressed file
-		cmd = exec.Command("psql", targetPgURI, "-f", filePath)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		
-		return cmd.Run()
 	}
+
+	// Direct restore from uncompressed file
+	cmd := exec.Command("psql", targetPgURI, "-f", filePath)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
 }
\ No newline at end of file
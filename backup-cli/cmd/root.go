@@ -30,4 +30,5 @@ func init() {
 	rootCmd.AddCommand(restoreCmd)
 	rootCmd.AddCommand(verifyCmd)
 	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(scheduleCmd)
 }
\ No newline at end of file
@@ -0,0 +1,435 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run backups on a recurring schedule",
+	Long: `Run backup-cli as a long-lived process that triggers backups on a cron
+expression or fixed interval, writes a JSON run-log per attempt, exposes
+health/metrics endpoints, and prunes old backups according to a retention
+policy. This lets backup-cli run as a sidecar without an external scheduler.`,
+	Run: runSchedule,
+}
+
+var (
+	scheduleCron           string
+	scheduleInterval       time.Duration
+	scheduleHealthAddr     string
+	scheduleRunLogDir      string
+	retentionDays          int
+	retentionCount         int
+	retentionPrefix        string
+	scheduleRunImmediately bool
+	vacuum                 bool
+	scheduleStateFile      string
+)
+
+// runState is loaded once at the start of runSchedule and updated/persisted
+// after each successful run; it's what --vacuum compares fresh checksums
+// against and what lets a restarted process pick up without double-uploading.
+var runState *scheduleState
+
+func init() {
+	scheduleCmd.Flags().StringVar(&scheduleCron, "cron", "", "Cron expression for backup schedule (e.g. \"0 2 * * *\")")
+	scheduleCmd.Flags().DurationVar(&scheduleInterval, "interval", 0, "Fixed interval between backups (e.g. 6h), alternative to --cron")
+	scheduleCmd.Flags().StringVar(&scheduleHealthAddr, "health-addr", ":8080", "Address to serve /healthz and /metrics on")
+	scheduleCmd.Flags().StringVar(&scheduleRunLogDir, "run-log-dir", "", "Directory to write per-run JSON logs (default: <output>/runlogs)")
+	scheduleCmd.Flags().IntVar(&retentionDays, "retention-days", 0, "Delete backups older than N days after each successful run (0 disables)")
+	scheduleCmd.Flags().IntVar(&retentionCount, "retention-count", 0, "Keep only the N most recent backups after each successful run (0 disables)")
+	scheduleCmd.Flags().StringVar(&retentionPrefix, "retention-prefix", "", "Only prune local files / S3 keys with this prefix")
+	scheduleCmd.Flags().BoolVar(&scheduleRunImmediately, "run-immediately", false, "Run a backup immediately on startup in addition to the schedule")
+	scheduleCmd.Flags().BoolVar(&vacuum, "vacuum", false, "Skip uploading a backup whose checksum matches the last successfully uploaded backup for that source (no-op backups)")
+	scheduleCmd.Flags().StringVar(&scheduleStateFile, "state-file", "", "File recording the last successful backup's timestamp/checksums, used by --vacuum and to avoid double-upload after a restart (default: <output>/.backup-state.json)")
+
+	// Reuse the same flags backupCmd exposes so a single config works for both.
+	scheduleCmd.Flags().StringVar(&dbType, "db", "mongo", "Database type: mongo, postgres, or both")
+	scheduleCmd.Flags().StringVar(&mongoURI, "mongo-uri", "", "MongoDB connection URI")
+	scheduleCmd.Flags().StringVar(&pgURI, "pg-uri", "", "PostgreSQL connection URI")
+	scheduleCmd.Flags().StringVar(&outputDir, "output", "/tmp/backups", "Output directory for backups")
+	scheduleCmd.Flags().StringVar(&storeURL, "store", "", "URL-style storage target, e.g. s3://bucket/prefix, gs://bucket/prefix, azure://container/prefix, s3+minio://bucket/prefix, file:///local/dir, or sftp://user@host/dir (takes precedence over --s3-bucket)")
+	scheduleCmd.Flags().StringVar(&s3Bucket, "s3-bucket", "", "Bucket/container for backup storage")
+	scheduleCmd.Flags().StringVar(&s3Region, "s3-region", "us-east-1", "Storage region (S3/MinIO)")
+	scheduleCmd.Flags().BoolVar(&encrypt, "encrypt", false, "Encrypt backup files")
+	scheduleCmd.Flags().BoolVar(&compress, "compress", true, "Compress backup files")
+	scheduleCmd.Flags().StringVar(&storageType, "storage", "s3", "Storage backend: s3, gcs, azure, or minio")
+	scheduleCmd.Flags().StringVar(&endpointURL, "endpoint-url", "", "Custom endpoint URL (required for --storage=minio)")
+	scheduleCmd.Flags().MarkDeprecated("s3-bucket", "use --store instead, e.g. --store s3://bucket/prefix")
+	scheduleCmd.Flags().MarkDeprecated("storage", "use --store instead, e.g. --store gs://bucket/prefix")
+}
+
+// runLog is the JSON record written to scheduleRunLogDir after each tick.
+type runLog struct {
+	StartedAt time.Time `json:"started_at"`
+	Duration  string    `json:"duration"`
+	DBType    string    `json:"db_type"`
+	Paths     []string  `json:"paths"`
+	Success   bool      `json:"success"`
+	Error     string    `json:"error,omitempty"`
+}
+
+var (
+	backupsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "backup_cli_backups_total",
+		Help: "Total number of scheduled backup attempts, by result.",
+	}, []string{"result"})
+	backupBytesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "backup_cli_backup_bytes_total",
+		Help: "Total bytes written across all successful scheduled backups.",
+	})
+	backupDurationSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "backup_cli_backup_duration_seconds",
+		Help:    "Duration of each scheduled backup attempt.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	lastRunSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "backup_cli_last_run_success",
+		Help: "1 if the most recent scheduled run succeeded, 0 otherwise.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(backupsTotal, backupBytesTotal, backupDurationSeconds, lastRunSuccess)
+}
+
+func runSchedule(cmd *cobra.Command, args []string) {
+	if scheduleCron == "" && scheduleInterval == 0 {
+		log.Fatal("Must specify either --cron or --interval")
+	}
+	if scheduleCron != "" && scheduleInterval != 0 {
+		log.Fatal("--cron and --interval are mutually exclusive")
+	}
+	if scheduleRunLogDir == "" {
+		scheduleRunLogDir = filepath.Join(outputDir, "runlogs")
+	}
+	if err := os.MkdirAll(scheduleRunLogDir, 0755); err != nil {
+		log.Fatalf("Failed to create run-log directory: %v", err)
+	}
+	if scheduleStateFile == "" {
+		scheduleStateFile = filepath.Join(outputDir, ".backup-state.json")
+	}
+	runState = loadScheduleState(scheduleStateFile)
+
+	var healthy int32
+	go serveHealth(scheduleHealthAddr, &healthy)
+
+	tick := func() {
+		runScheduledBackup(&healthy)
+	}
+
+	if scheduleRunImmediately {
+		tick()
+	}
+
+	if scheduleInterval != 0 {
+		ticker := time.NewTicker(scheduleInterval)
+		defer ticker.Stop()
+		fmt.Printf("Scheduled backups every %s\n", scheduleInterval)
+		for range ticker.C {
+			tick()
+		}
+		return
+	}
+
+	c := cron.New()
+	if _, err := c.AddFunc(scheduleCron, tick); err != nil {
+		log.Fatalf("Invalid cron expression %q: %v", scheduleCron, err)
+	}
+	fmt.Printf("Scheduled backups with cron expression %q\n", scheduleCron)
+	c.Run() // blocks forever, driving ticks on its own goroutine scheduler
+}
+
+func runScheduledBackup(healthy *int32) {
+	start := time.Now()
+	entry := runLog{StartedAt: start, DBType: dbType}
+
+	paths, err := performBackup()
+	entry.Paths = paths
+	entry.Duration = time.Since(start).String()
+	entry.Success = err == nil
+
+	backupDurationSeconds.Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		entry.Error = err.Error()
+		backupsTotal.WithLabelValues("failure").Inc()
+		lastRunSuccess.Set(0)
+		atomicStoreHealthy(healthy, 0)
+		log.Printf("Scheduled backup failed: %v", err)
+		notify(NotifyEvent{Timestamp: start, DBType: dbType, OutputPath: strings.Join(paths, ", "), Duration: time.Since(start), Error: err.Error()})
+	} else {
+		backupsTotal.WithLabelValues("success").Inc()
+		lastRunSuccess.Set(1)
+		atomicStoreHealthy(healthy, 1)
+		var totalBytes int64
+		for _, p := range paths {
+			if info, statErr := os.Stat(p); statErr == nil {
+				backupBytesTotal.Add(float64(info.Size()))
+				totalBytes += info.Size()
+			}
+		}
+		notify(NotifyEvent{Timestamp: start, DBType: dbType, OutputPath: strings.Join(paths, ", "), SizeBytes: totalBytes, Duration: time.Since(start)})
+		runState.LastSuccessAt = start
+		if err := runState.save(scheduleStateFile); err != nil {
+			log.Printf("Warning: failed to persist backup state: %v", err)
+		}
+		pruneOldBackups()
+	}
+
+	writeRunLog(entry)
+}
+
+// performBackup runs the same mongo/postgres/upload steps runBackup does,
+// returning the local paths it produced so the caller can account for
+// bytes written and apply retention.
+func performBackup() ([]string, error) {
+	timestamp := time.Now().Format("20060102-150405")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	var backupPaths []string
+
+	if dbType == "mongo" || dbType == "both" {
+		uri := mongoURI
+		if uri == "" {
+			uri = os.Getenv("MONGO_URI")
+		}
+		if uri == "" {
+			return backupPaths, fmt.Errorf("MongoDB URI not provided (use --mongo-uri or MONGO_URI env var)")
+		}
+		notifyBackupStart("mongo")
+		path, err := backupMongoDB(uri, timestamp)
+		if err != nil {
+			return backupPaths, fmt.Errorf("MongoDB backup failed: %w", err)
+		}
+		backupPaths = append(backupPaths, path)
+	}
+
+	if dbType == "postgres" || dbType == "both" {
+		uri := pgURI
+		if uri == "" {
+			uri = os.Getenv("POSTGRES_URI")
+		}
+		if uri == "" {
+			return backupPaths, fmt.Errorf("PostgreSQL URI not provided (use --pg-uri or POSTGRES_URI env var)")
+		}
+		notifyBackupStart("postgres")
+		path, err := backupPostgreSQL(uri, timestamp)
+		if err != nil {
+			return backupPaths, fmt.Errorf("PostgreSQL backup failed: %w", err)
+		}
+		backupPaths = append(backupPaths, path)
+	}
+
+	if storageConfigured(storeURL, s3Bucket) {
+		backend, err := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
+		if err != nil {
+			return backupPaths, fmt.Errorf("invalid storage configuration: %w", err)
+		}
+		for _, path := range backupPaths {
+			key := backupSourceKey(path)
+			checksum := readChecksum(path)
+
+			if vacuum && checksum != "" && runState.Checksums[key] == checksum {
+				fmt.Printf("Skipping upload of %s: unchanged since last successful backup (--vacuum)\n", path)
+				continue
+			}
+
+			if err := uploadBackup(backend, path); err != nil {
+				return backupPaths, fmt.Errorf("failed to upload %s: %w", path, err)
+			}
+			if checksum != "" {
+				runState.Checksums[key] = checksum
+			}
+		}
+	}
+
+	return backupPaths, nil
+}
+
+// backupSourceKey maps a backup file path to the --db source that produced
+// it (e.g. "mongo_mydb_20240101-000000.archive" -> "mongo"), the key
+// runState.Checksums is keyed by.
+func backupSourceKey(path string) string {
+	base := filepath.Base(path)
+	if idx := strings.Index(base, "_"); idx > 0 {
+		return base[:idx]
+	}
+	return base
+}
+
+// readChecksum returns the SHA256 createChecksum wrote alongside path, or
+// "" if no sidecar exists.
+func readChecksum(path string) string {
+	data, err := os.ReadFile(path + ".sha256")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func writeRunLog(entry runLog) {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		log.Printf("Warning: failed to marshal run log: %v", err)
+		return
+	}
+	name := fmt.Sprintf("run_%s.json", entry.StartedAt.Format("20060102-150405"))
+	if err := os.WriteFile(filepath.Join(scheduleRunLogDir, name), data, 0644); err != nil {
+		log.Printf("Warning: failed to write run log: %v", err)
+	}
+}
+
+// pruneOldBackups applies --retention-days / --retention-count to local
+// backup files (and their .sha256 siblings) and, if an S3 bucket is
+// configured, to the matching S3 objects.
+func pruneOldBackups() {
+	if retentionDays <= 0 && retentionCount <= 0 {
+		return
+	}
+
+	files := localBackupFiles(retentionPrefix)
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	for i, f := range files {
+		expired := retentionDays > 0 && time.Since(f.modTime) > time.Duration(retentionDays)*24*time.Hour
+		overCount := retentionCount > 0 && i >= retentionCount
+		if !expired && !overCount {
+			continue
+		}
+		if err := os.Remove(f.path); err != nil {
+			log.Printf("Warning: failed to prune %s: %v", f.path, err)
+			continue
+		}
+		os.Remove(f.path + ".sha256") // best effort; sidecar may not exist
+		fmt.Printf("Pruned expired backup: %s\n", f.path)
+	}
+
+	if storageConfigured(storeURL, s3Bucket) {
+		pruneRemoteBackups()
+	}
+}
+
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+func localBackupFiles(prefix string) []backupFile {
+	matches, _ := filepath.Glob(filepath.Join(outputDir, "*"))
+	var files []backupFile
+	for _, m := range matches {
+		if strings.HasSuffix(m, ".sha256") || m == scheduleStateFile {
+			continue
+		}
+		base := filepath.Base(m)
+		if prefix != "" && !strings.HasPrefix(base, prefix) {
+			continue
+		}
+		info, err := os.Stat(m)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, backupFile{path: m, modTime: info.ModTime()})
+	}
+	return files
+}
+
+func filterSha256Sidecars(objects []StorageObject) []StorageObject {
+	filtered := objects[:0]
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, ".sha256") {
+			continue
+		}
+		filtered = append(filtered, obj)
+	}
+	return filtered
+}
+
+func pruneRemoteBackups() {
+	backend, err := resolveStorageBackend(storeURL, storageType, s3Bucket, s3Region, endpointURL)
+	if err != nil {
+		log.Printf("Warning: invalid storage configuration for retention: %v", err)
+		return
+	}
+
+	ctx := context.Background()
+	objects, err := backend.List(ctx, retentionPrefix)
+	if err != nil {
+		log.Printf("Warning: failed to list remote objects for retention: %v", err)
+		return
+	}
+
+	objects = filterSha256Sidecars(objects)
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].LastModified.After(objects[j].LastModified)
+	})
+
+	for i, obj := range objects {
+		expired := retentionDays > 0 && time.Since(obj.LastModified) > time.Duration(retentionDays)*24*time.Hour
+		overCount := retentionCount > 0 && i >= retentionCount
+		if !expired && !overCount {
+			continue
+		}
+		if err := backend.Delete(ctx, obj.Key); err != nil {
+			log.Printf("Warning: failed to prune %s: %v", obj.Key, err)
+			continue
+		}
+		backend.Delete(ctx, obj.Key+".sha256") // best effort; sidecar may not exist
+		fmt.Printf("Pruned expired remote backup: %s\n", obj.Key)
+	}
+}
+
+func serveHealth(addr string, healthy *int32) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if atomicLoadHealthy(healthy) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte("unhealthy: last backup failed\n"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok\n"))
+	})
+	mux.Handle("/metrics", promhttp.Handler())
+
+	log.Printf("Health endpoint listening on %s", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Printf("Health server stopped: %v", err)
+	}
+}
+
+var healthMu sync.Mutex
+
+func atomicStoreHealthy(healthy *int32, v int32) {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	*healthy = v
+}
+
+func atomicLoadHealthy(healthy *int32) int32 {
+	healthMu.Lock()
+	defer healthMu.Unlock()
+	return *healthy
+}
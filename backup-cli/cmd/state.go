@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+)
+
+// scheduleState is the on-disk record a recurring (schedule/--auto) run
+// persists after each successful backup, so --vacuum can tell whether a
+// source has changed since last time and a restarted process doesn't
+// re-upload a backup it already shipped.
+type scheduleState struct {
+	LastSuccessAt time.Time         `json:"last_success_at"`
+	Checksums     map[string]string `json:"checksums"` // source key (e.g. "mongo") -> SHA256 of its last uploaded backup
+}
+
+// loadScheduleState reads path, returning a fresh empty state if path is
+// unset or the file doesn't exist or can't be parsed; a missing/corrupt
+// state file is never fatal, it just means --vacuum has nothing to compare
+// against yet.
+func loadScheduleState(path string) *scheduleState {
+	state := &scheduleState{Checksums: make(map[string]string)}
+	if path == "" {
+		return state
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		log.Printf("Warning: failed to parse state file %s, starting fresh: %v", path, err)
+		return &scheduleState{Checksums: make(map[string]string)}
+	}
+	if state.Checksums == nil {
+		state.Checksums = make(map[string]string)
+	}
+	return state
+}
+
+func (s *scheduleState) save(path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
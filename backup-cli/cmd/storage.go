@@ -0,0 +1,895 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"google.golang.org/api/iterator"
+)
+
+// StorageObject is a provider-agnostic view of a stored backup artifact.
+type StorageObject struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+	StorageClass string
+}
+
+// StorageBackend is implemented by every supported cloud storage provider.
+// backupCmd, listCmd, restoreCmd and scheduleCmd talk to storage exclusively
+// through this interface so they work the same way regardless of
+// --storage.
+type StorageBackend interface {
+	Upload(ctx context.Context, localPath, key string) error
+	Download(ctx context.Context, key, localPath string) error
+	List(ctx context.Context, prefix string) ([]StorageObject, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (StorageObject, error)
+	// HasSidecar reports whether a "<key>.sha256" object exists alongside key.
+	HasSidecar(ctx context.Context, key string) bool
+}
+
+// newStorageBackend constructs the StorageBackend selected by --storage.
+// bucket is the bucket/container name in all providers; endpointURL and
+// pathStyle only apply to s3/minio.
+func newStorageBackend(kind, bucket, region, endpointURL string) (StorageBackend, error) {
+	switch kind {
+	case "", "s3":
+		return &s3Backend{bucket: bucket, region: region}, nil
+	case "minio":
+		if endpointURL == "" {
+			return nil, fmt.Errorf("--endpoint-url is required for --storage=minio")
+		}
+		return &s3Backend{bucket: bucket, region: region, endpointURL: endpointURL, pathStyle: true}, nil
+	case "gcs":
+		return &gcsBackend{bucket: bucket}, nil
+	case "azure":
+		return &azureBackend{container: bucket}, nil
+	default:
+		return nil, fmt.Errorf("unsupported --storage value %q (want s3, gcs, azure, or minio)", kind)
+	}
+}
+
+// resolveStorageBackend builds the StorageBackend a command should use,
+// preferring the URL-style --store flag (e.g. "gs://bucket/prefix") over the
+// older --storage/--s3-bucket/--s3-region flag trio, which is kept working
+// for backward compatibility. Returns (nil, nil) if neither is set.
+func resolveStorageBackend(storeURL, kind, bucket, region, endpointURL string) (StorageBackend, error) {
+	if storeURL != "" {
+		return newStorageBackendFromURL(storeURL, region, endpointURL)
+	}
+	if bucket != "" {
+		return newStorageBackend(kind, bucket, region, endpointURL)
+	}
+	return nil, nil
+}
+
+// storageConfigured reports whether a remote store was given via either the
+// new --store flag or the legacy --s3-bucket flag.
+func storageConfigured(storeURL, bucket string) bool {
+	return storeURL != "" || bucket != ""
+}
+
+// storageDescription returns a short label for the configured store, for use
+// in log/status messages.
+func storageDescription(storeURL, kind string) string {
+	if storeURL != "" {
+		if u, err := url.Parse(storeURL); err == nil {
+			return u.Scheme
+		}
+		return storeURL
+	}
+	return kind
+}
+
+// newStorageBackendFromURL parses a URL-style store address and returns the
+// matching backend:
+//
+//	s3://bucket/prefix          AWS S3
+//	s3+minio://bucket/prefix    MinIO / other S3-compatible endpoint (requires endpointURL)
+//	gs://bucket/prefix          Google Cloud Storage
+//	azure://container/prefix    Azure Blob Storage
+//	file:///local/dir           Local filesystem
+//	sftp://user@host:port/dir   SFTP
+//
+// Any path segment after the bucket/container becomes a fixed key prefix
+// that's transparently joined onto every key the caller passes in.
+func newStorageBackendFromURL(raw, region, endpointURL string) (StorageBackend, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --store value %q: %v", raw, err)
+	}
+
+	var backend StorageBackend
+	var prefix string
+
+	switch u.Scheme {
+	case "s3":
+		backend, prefix = &s3Backend{bucket: u.Host, region: region}, u.Path
+	case "s3+minio":
+		if endpointURL == "" {
+			return nil, fmt.Errorf("--endpoint-url is required for --store %s://...", u.Scheme)
+		}
+		backend, prefix = &s3Backend{bucket: u.Host, region: region, endpointURL: endpointURL, pathStyle: true}, u.Path
+	case "gs", "gcs":
+		backend, prefix = &gcsBackend{bucket: u.Host}, u.Path
+	case "azure", "az":
+		backend, prefix = &azureBackend{container: u.Host}, u.Path
+	case "file":
+		return &localBackend{baseDir: u.Path}, nil
+	case "sftp":
+		return newSFTPBackend(u)
+	default:
+		return nil, fmt.Errorf("unsupported --store scheme %q (want s3, s3+minio, gs, azure, file, or sftp)", u.Scheme)
+	}
+
+	return withPrefix(backend, strings.Trim(prefix, "/")), nil
+}
+
+// withPrefix wraps backend so every key it's given is transparently joined
+// onto prefix, letting a single bucket/container host multiple --store
+// addresses (e.g. "gs://bucket/team-a" and "gs://bucket/team-b") without the
+// callers in backup.go/restore.go/schedule.go/list.go needing to know about it.
+func withPrefix(backend StorageBackend, prefix string) StorageBackend {
+	if prefix == "" {
+		return backend
+	}
+	return &prefixedBackend{StorageBackend: backend, prefix: prefix}
+}
+
+type prefixedBackend struct {
+	StorageBackend
+	prefix string
+}
+
+func (b *prefixedBackend) join(key string) string {
+	return path.Join(b.prefix, key)
+}
+
+func (b *prefixedBackend) Upload(ctx context.Context, localPath, key string) error {
+	return b.StorageBackend.Upload(ctx, localPath, b.join(key))
+}
+
+func (b *prefixedBackend) Download(ctx context.Context, key, localPath string) error {
+	return b.StorageBackend.Download(ctx, b.join(key), localPath)
+}
+
+func (b *prefixedBackend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	objects, err := b.StorageBackend.List(ctx, b.join(prefix))
+	if err != nil {
+		return nil, err
+	}
+	for i := range objects {
+		objects[i].Key = strings.TrimPrefix(strings.TrimPrefix(objects[i].Key, b.prefix), "/")
+	}
+	return objects, nil
+}
+
+func (b *prefixedBackend) Delete(ctx context.Context, key string) error {
+	return b.StorageBackend.Delete(ctx, b.join(key))
+}
+
+func (b *prefixedBackend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	obj, err := b.StorageBackend.Stat(ctx, b.join(key))
+	if err != nil {
+		return StorageObject{}, err
+	}
+	obj.Key = strings.TrimPrefix(strings.TrimPrefix(obj.Key, b.prefix), "/")
+	return obj, nil
+}
+
+func (b *prefixedBackend) HasSidecar(ctx context.Context, key string) bool {
+	return b.StorageBackend.HasSidecar(ctx, b.join(key))
+}
+
+// --- S3 / S3-compatible (MinIO) -------------------------------------------
+
+type s3Backend struct {
+	bucket      string
+	region      string
+	endpointURL string // set for MinIO and other S3-compatible endpoints
+	pathStyle   bool
+}
+
+func (b *s3Backend) client() (*s3.S3, error) {
+	cfg := &aws.Config{Region: aws.String(b.region)}
+	if b.endpointURL != "" {
+		cfg.Endpoint = aws.String(b.endpointURL)
+		cfg.S3ForcePathStyle = aws.Bool(b.pathStyle)
+		if key := os.Getenv("MINIO_ACCESS_KEY"); key != "" {
+			cfg.Credentials = credentials.NewStaticCredentials(key, os.Getenv("MINIO_SECRET_KEY"), "")
+		}
+	}
+	sess, err := session.NewSession(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %v", err)
+	}
+	return s3.New(sess), nil
+}
+
+// Upload sends localPath to key. Files larger than --upload-part-size go
+// through multipartUpload (parallel, resumable via --resume); smaller files
+// use a single PutObject call.
+func (b *s3Backend) Upload(ctx context.Context, localPath, key string) error {
+	svc, err := b.client()
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %v", err)
+	}
+
+	partSize := uploadPartSizeMB * 1024 * 1024
+	if partSize < minMultipartPartSize {
+		partSize = minMultipartPartSize
+	}
+
+	if info.Size() <= partSize {
+		return b.putObject(ctx, svc, localPath, key)
+	}
+	return b.multipartUpload(ctx, svc, localPath, key, info.Size(), partSize)
+}
+
+func (b *s3Backend) putObject(ctx context.Context, svc *s3.S3, localPath, key string) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+		Body:   file,
+	}
+	if sseMode != "" {
+		input.ServerSideEncryption = aws.String(sseMode)
+		if sseKMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(sseKMSKeyID)
+		}
+	}
+	if uploadStorageClass != "" {
+		input.StorageClass = aws.String(uploadStorageClass)
+	}
+	if uploadACL != "" {
+		input.ACL = aws.String(uploadACL)
+	}
+
+	if _, err := svc.PutObjectWithContext(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload to %s: %v", b.describe(), err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Download(ctx context.Context, key, localPath string) error {
+	svc, err := b.client()
+	if err != nil {
+		return err
+	}
+	result, err := svc.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download from %s: %v", b.describe(), err)
+	}
+	defer result.Body.Close()
+
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer out.Close()
+
+	src := io.Reader(result.Body)
+	if total := aws.Int64Value(result.ContentLength); total > 0 {
+		src = wrapProgressReader(src, key, total)
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		return fmt.Errorf("failed to write downloaded data: %v", err)
+	}
+	return nil
+}
+
+func (b *s3Backend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	svc, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	input := &s3.ListObjectsV2Input{Bucket: aws.String(b.bucket)}
+	if prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	result, err := svc.ListObjectsV2WithContext(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects in %s: %v", b.describe(), err)
+	}
+
+	objects := make([]StorageObject, 0, len(result.Contents))
+	for _, obj := range result.Contents {
+		objects = append(objects, StorageObject{
+			Key:          aws.StringValue(obj.Key),
+			Size:         aws.Int64Value(obj.Size),
+			LastModified: aws.TimeValue(obj.LastModified),
+			StorageClass: aws.StringValue(obj.StorageClass),
+		})
+	}
+	return objects, nil
+}
+
+func (b *s3Backend) Delete(ctx context.Context, key string) error {
+	svc, err := b.client()
+	if err != nil {
+		return err
+	}
+	_, err = svc.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s from %s: %v", key, b.describe(), err)
+	}
+	return nil
+}
+
+func (b *s3Backend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	svc, err := b.client()
+	if err != nil {
+		return StorageObject{}, err
+	}
+	out, err := svc.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to stat %s in %s: %v", key, b.describe(), err)
+	}
+	return StorageObject{
+		Key:          key,
+		Size:         aws.Int64Value(out.ContentLength),
+		LastModified: aws.TimeValue(out.LastModified),
+		StorageClass: aws.StringValue(out.StorageClass),
+	}, nil
+}
+
+func (b *s3Backend) HasSidecar(ctx context.Context, key string) bool {
+	_, err := b.Stat(ctx, key+".sha256")
+	return err == nil
+}
+
+func (b *s3Backend) describe() string {
+	if b.endpointURL != "" {
+		return fmt.Sprintf("%s (bucket %s)", b.endpointURL, b.bucket)
+	}
+	return fmt.Sprintf("s3://%s", b.bucket)
+}
+
+// --- Google Cloud Storage ---------------------------------------------
+
+type gcsBackend struct {
+	bucket string
+}
+
+func (b *gcsBackend) client(ctx context.Context) (*storage.Client, error) {
+	return storage.NewClient(ctx)
+}
+
+func (b *gcsBackend) Upload(ctx context.Context, localPath, key string) error {
+	client, err := b.client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GCS client: %v", err)
+	}
+	defer client.Close()
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	w := client.Bucket(b.bucket).Object(key).NewWriter(ctx)
+	if _, err := io.Copy(w, file); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to upload to gs://%s/%s: %v", b.bucket, key, err)
+	}
+	return w.Close()
+}
+
+func (b *gcsBackend) Download(ctx context.Context, key, localPath string) error {
+	client, err := b.client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GCS client: %v", err)
+	}
+	defer client.Close()
+
+	r, err := client.Bucket(b.bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to download gs://%s/%s: %v", b.bucket, key, err)
+	}
+	defer r.Close()
+
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, r)
+	return err
+}
+
+func (b *gcsBackend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	client, err := b.client(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %v", err)
+	}
+	defer client.Close()
+
+	var objects []StorageObject
+	it := client.Bucket(b.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list gs://%s: %v", b.bucket, err)
+		}
+		objects = append(objects, StorageObject{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			LastModified: attrs.Updated,
+			StorageClass: attrs.StorageClass,
+		})
+	}
+	return objects, nil
+}
+
+func (b *gcsBackend) Delete(ctx context.Context, key string) error {
+	client, err := b.client(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create GCS client: %v", err)
+	}
+	defer client.Close()
+
+	if err := client.Bucket(b.bucket).Object(key).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete gs://%s/%s: %v", b.bucket, key, err)
+	}
+	return nil
+}
+
+func (b *gcsBackend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	client, err := b.client(ctx)
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to create GCS client: %v", err)
+	}
+	defer client.Close()
+
+	attrs, err := client.Bucket(b.bucket).Object(key).Attrs(ctx)
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to stat gs://%s/%s: %v", b.bucket, key, err)
+	}
+	return StorageObject{
+		Key:          attrs.Name,
+		Size:         attrs.Size,
+		LastModified: attrs.Updated,
+		StorageClass: attrs.StorageClass,
+	}, nil
+}
+
+func (b *gcsBackend) HasSidecar(ctx context.Context, key string) bool {
+	_, err := b.Stat(ctx, key+".sha256")
+	return err == nil
+}
+
+// --- Azure Blob Storage --------------------------------------------------
+
+type azureBackend struct {
+	container string
+}
+
+func (b *azureBackend) containerURL() (azblob.ContainerURL, error) {
+	accountName := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	accountKey := os.Getenv("AZURE_STORAGE_KEY")
+	if accountName == "" || accountKey == "" {
+		return azblob.ContainerURL{}, fmt.Errorf("AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_KEY must be set")
+	}
+
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return azblob.ContainerURL{}, fmt.Errorf("invalid Azure credentials: %v", err)
+	}
+
+	pipeline := azblob.NewPipeline(credential, azblob.PipelineOptions{})
+	u, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net/%s", accountName, b.container))
+	if err != nil {
+		return azblob.ContainerURL{}, err
+	}
+	return azblob.NewContainerURL(*u, pipeline), nil
+}
+
+func (b *azureBackend) Upload(ctx context.Context, localPath, key string) error {
+	containerURL, err := b.containerURL()
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer file.Close()
+
+	blobURL := containerURL.NewBlockBlobURL(key)
+	_, err = azblob.UploadFileToBlockBlob(ctx, file, blobURL, azblob.UploadToBlockBlobOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to upload to azure blob %s/%s: %v", b.container, key, err)
+	}
+	return nil
+}
+
+func (b *azureBackend) Download(ctx context.Context, key, localPath string) error {
+	containerURL, err := b.containerURL()
+	if err != nil {
+		return err
+	}
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer out.Close()
+
+	blobURL := containerURL.NewBlockBlobURL(key)
+	return azblob.DownloadBlobToFile(ctx, blobURL.BlobURL, 0, azblob.CountToEnd, out, azblob.DownloadFromBlobOptions{})
+}
+
+func (b *azureBackend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	containerURL, err := b.containerURL()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []StorageObject
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := containerURL.ListBlobsFlatSegment(ctx, marker, azblob.ListBlobsSegmentOptions{Prefix: prefix})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list azure container %s: %v", b.container, err)
+		}
+		for _, blob := range resp.Segment.BlobItems {
+			objects = append(objects, StorageObject{
+				Key:          blob.Name,
+				Size:         *blob.Properties.ContentLength,
+				LastModified: blob.Properties.LastModified,
+			})
+		}
+		marker = resp.NextMarker
+	}
+	return objects, nil
+}
+
+func (b *azureBackend) Delete(ctx context.Context, key string) error {
+	containerURL, err := b.containerURL()
+	if err != nil {
+		return err
+	}
+	blobURL := containerURL.NewBlockBlobURL(key)
+	_, err = blobURL.Delete(ctx, azblob.DeleteSnapshotsOptionNone, azblob.BlobAccessConditions{})
+	if err != nil {
+		return fmt.Errorf("failed to delete azure blob %s/%s: %v", b.container, key, err)
+	}
+	return nil
+}
+
+func (b *azureBackend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	containerURL, err := b.containerURL()
+	if err != nil {
+		return StorageObject{}, err
+	}
+	blobURL := containerURL.NewBlockBlobURL(key)
+	props, err := blobURL.GetProperties(ctx, azblob.BlobAccessConditions{}, azblob.ClientProvidedKeyOptions{})
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to stat azure blob %s/%s: %v", b.container, key, err)
+	}
+	return StorageObject{
+		Key:          key,
+		Size:         props.ContentLength(),
+		LastModified: props.LastModified(),
+	}, nil
+}
+
+func (b *azureBackend) HasSidecar(ctx context.Context, key string) bool {
+	_, err := b.Stat(ctx, key+".sha256")
+	return err == nil
+}
+
+// --- Local filesystem ------------------------------------------------------
+
+// localBackend stores objects as plain files under baseDir, for on-prem
+// setups with no object store (e.g. an NFS mount given as --store
+// file:///mnt/backups).
+type localBackend struct {
+	baseDir string
+}
+
+func (b *localBackend) resolve(key string) string {
+	return filepath.Join(b.baseDir, key)
+}
+
+func (b *localBackend) Upload(ctx context.Context, localPath, key string) error {
+	dst := b.resolve(key)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %v", filepath.Dir(dst), err)
+	}
+
+	in, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", dst, err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (b *localBackend) Download(ctx context.Context, key, localPath string) error {
+	src := b.resolve(key)
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (b *localBackend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	matches, err := filepath.Glob(filepath.Join(b.baseDir, prefix+"*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %v", b.baseDir, err)
+	}
+
+	objects := make([]StorageObject, 0, len(matches))
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		rel, err := filepath.Rel(b.baseDir, m)
+		if err != nil {
+			rel = filepath.Base(m)
+		}
+		objects = append(objects, StorageObject{Key: rel, Size: info.Size(), LastModified: info.ModTime()})
+	}
+	return objects, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(b.resolve(key)); err != nil {
+		return fmt.Errorf("failed to delete %s: %v", key, err)
+	}
+	return nil
+}
+
+func (b *localBackend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	p := b.resolve(key)
+	info, err := os.Stat(p)
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to stat %s: %v", p, err)
+	}
+	return StorageObject{Key: key, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+func (b *localBackend) HasSidecar(ctx context.Context, key string) bool {
+	_, err := os.Stat(b.resolve(key + ".sha256"))
+	return err == nil
+}
+
+// --- SFTP --------------------------------------------------------------
+
+// sftpBackend stores objects as plain files under baseDir on a remote host
+// reachable over SSH. Credentials come from the store URL's userinfo (if
+// given) and the SFTP_PASSWORD / SFTP_PRIVATE_KEY env vars, following the
+// same env-var convention as the MinIO and Azure backends above.
+type sftpBackend struct {
+	addr    string // host:port
+	user    string
+	baseDir string
+}
+
+func newSFTPBackend(u *url.URL) (*sftpBackend, error) {
+	user := u.User.Username()
+	if user == "" {
+		user = os.Getenv("SFTP_USER")
+	}
+	if user == "" {
+		return nil, fmt.Errorf("no user in --store sftp:// URL and SFTP_USER is not set")
+	}
+
+	addr := u.Host
+	if u.Port() == "" {
+		addr = addr + ":22"
+	}
+
+	return &sftpBackend{addr: addr, user: user, baseDir: u.Path}, nil
+}
+
+func (b *sftpBackend) client() (*sftp.Client, *ssh.Client, error) {
+	config := &ssh.ClientConfig{
+		User:            b.user,
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         30 * time.Second,
+	}
+
+	if keyPath := os.Getenv("SFTP_PRIVATE_KEY"); keyPath != "" {
+		key, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read SFTP_PRIVATE_KEY: %v", err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse SFTP_PRIVATE_KEY: %v", err)
+		}
+		config.Auth = []ssh.AuthMethod{ssh.PublicKeys(signer)}
+	} else {
+		config.Auth = []ssh.AuthMethod{ssh.Password(os.Getenv("SFTP_PASSWORD"))}
+	}
+
+	conn, err := ssh.Dial("tcp", b.addr, config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to %s: %v", b.addr, err)
+	}
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to start SFTP session on %s: %v", b.addr, err)
+	}
+
+	return client, conn, nil
+}
+
+func (b *sftpBackend) resolve(key string) string {
+	return path.Join(b.baseDir, key)
+}
+
+func (b *sftpBackend) Upload(ctx context.Context, localPath, key string) error {
+	client, conn, err := b.client()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	dst := b.resolve(key)
+	if err := client.MkdirAll(path.Dir(dst)); err != nil {
+		return fmt.Errorf("failed to create %s on %s: %v", path.Dir(dst), b.addr, err)
+	}
+
+	in, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", err)
+	}
+	defer in.Close()
+
+	out, err := client.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create %s on %s: %v", dst, b.addr, err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (b *sftpBackend) Download(ctx context.Context, key, localPath string) error {
+	client, conn, err := b.client()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	src := b.resolve(key)
+	in, err := client.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s on %s: %v", src, b.addr, err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to create local file: %v", err)
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+func (b *sftpBackend) List(ctx context.Context, prefix string) ([]StorageObject, error) {
+	client, conn, err := b.client()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	entries, err := client.ReadDir(b.baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s on %s: %v", b.baseDir, b.addr, err)
+	}
+
+	objects := make([]StorageObject, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		objects = append(objects, StorageObject{Key: e.Name(), Size: e.Size(), LastModified: e.ModTime()})
+	}
+	return objects, nil
+}
+
+func (b *sftpBackend) Delete(ctx context.Context, key string) error {
+	client, conn, err := b.client()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	if err := client.Remove(b.resolve(key)); err != nil {
+		return fmt.Errorf("failed to delete %s on %s: %v", key, b.addr, err)
+	}
+	return nil
+}
+
+func (b *sftpBackend) Stat(ctx context.Context, key string) (StorageObject, error) {
+	client, conn, err := b.client()
+	if err != nil {
+		return StorageObject{}, err
+	}
+	defer conn.Close()
+	defer client.Close()
+
+	p := b.resolve(key)
+	info, err := client.Stat(p)
+	if err != nil {
+		return StorageObject{}, fmt.Errorf("failed to stat %s on %s: %v", p, b.addr, err)
+	}
+	return StorageObject{Key: key, Size: info.Size(), LastModified: info.ModTime()}, nil
+}
+
+func (b *sftpBackend) HasSidecar(ctx context.Context, key string) bool {
+	_, err := b.Stat(ctx, key+".sha256")
+	return err == nil
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -48,40 +49,78 @@ func runVerify(cmd *cobra.Command, args []string) {
 		}
 	}
 	
-	// Verify existing checksum
+	// Verify existing checksum. For encrypted backups this only proves the
+	// ciphertext is intact; it does not require the data key.
 	if err := verifyChecksum(verifyFile, checksumPath); err != nil {
+		notify(NotifyEvent{Timestamp: time.Now(), DBType: "verify", OutputPath: verifyFile, Error: err.Error()})
 		fmt.Printf("Verification failed: %v\n", err)
 		os.Exit(1)
 	}
-	
+
 	fmt.Printf("✓ Backup file verification successful: %s\n", verifyFile)
+
+	if verifyPlaintext {
+		if err := verifyDecryptedPlaintext(verifyFile); err != nil {
+			notify(NotifyEvent{Timestamp: time.Now(), DBType: "verify", OutputPath: verifyFile, Error: err.Error()})
+			fmt.Printf("Plaintext verification failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Plaintext verification successful: %s\n", verifyFile)
+	}
+
+	notify(NotifyEvent{Timestamp: time.Now(), DBType: "verify", OutputPath: verifyFile})
+}
+
+// verifyDecryptedPlaintext decrypts verifyFile and checks the result against
+// its <file>.plain.sha256 sidecar, proving the data key and ciphertext both
+// recover the original plaintext.
+func verifyDecryptedPlaintext(verifyFile string) error {
+	if !isEncryptedBackup(verifyFile) {
+		return fmt.Errorf("%s is not an encrypted backup", verifyFile)
+	}
+
+	plainChecksumPath := verifyFile + ".plain.sha256"
+	expected, err := os.ReadFile(plainChecksumPath)
+	if err != nil {
+		return fmt.Errorf("failed to read plaintext checksum file: %v", err)
+	}
+
+	tmpPath := verifyFile + ".plaintmp"
+	if err := decryptFile(verifyFile, tmpPath); err != nil {
+		return fmt.Errorf("failed to decrypt: %v", err)
+	}
+	defer os.Remove(tmpPath)
+
+	return verifyChecksumAgainst(tmpPath, strings.TrimSpace(string(expected)))
 }
 
 func verifyChecksum(filePath, checksumPath string) error {
-	// Read expected checksum
 	expectedChecksum, err := os.ReadFile(checksumPath)
 	if err != nil {
 		return fmt.Errorf("failed to read checksum file: %v", err)
 	}
-	
-	// Calculate actual checksum
+	return verifyChecksumAgainst(filePath, strings.TrimSpace(string(expectedChecksum)))
+}
+
+// verifyChecksumAgainst hashes filePath and compares it against an
+// already-known expected checksum.
+func verifyChecksumAgainst(filePath, expectedStr string) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open backup file: %v", err)
 	}
 	defer file.Close()
-	
+
 	hash := sha256.New()
 	if _, err := io.Copy(hash, file); err != nil {
 		return fmt.Errorf("failed to calculate checksum: %v", err)
 	}
-	
+
 	actualChecksum := fmt.Sprintf("%x", hash.Sum(nil))
-	expectedStr := strings.TrimSpace(string(expectedChecksum))
-	
+
 	if expectedStr != actualChecksum {
 		return fmt.Errorf("checksum mismatch:\n  Expected: %s\n  Actual:   %s", expectedStr, actualChecksum)
 	}
-	
+
 	return nil
 }
\ No newline at end of file
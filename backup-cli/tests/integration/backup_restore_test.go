@@ -0,0 +1,256 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"backup-cli/cmd"
+)
+
+// TestBackupRestoreCycle_Mongo seeds a real MongoDB container, backs it
+// up through cmd.BackupMongo, wipes the database, restores the archive
+// through cmd.RestoreMongo, and diffs the result against the fixture for
+// exact equality.
+func TestBackupRestoreCycle_Mongo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	mongo := startMongo(t, ctx)
+	if err := seedMongo(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to seed mongo: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	archivePath, err := cmd.BackupMongo(mongo.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err != nil {
+		t.Fatalf("BackupMongo failed: %v", err)
+	}
+
+	if err := cmd.VerifyBackup(archivePath); err != nil {
+		t.Fatalf("VerifyBackup rejected a good archive: %v", err)
+	}
+
+	if err := dropMongoDatabase(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to wipe mongo database: %v", err)
+	}
+
+	if err := cmd.RestoreMongo(mongo.URI, archivePath); err != nil {
+		t.Fatalf("RestoreMongo failed: %v", err)
+	}
+
+	got, err := fetchMongo(ctx, mongo.URI)
+	if err != nil {
+		t.Fatalf("failed to fetch restored mongo data: %v", err)
+	}
+	if !reflect.DeepEqual(got, fixtureRows()) {
+		t.Fatalf("restored mongo data does not match fixture:\n got:  %+v\n want: %+v", got, fixtureRows())
+	}
+}
+
+// TestBackupRestoreCycle_Postgres does the same round trip against a
+// real PostgreSQL container.
+func TestBackupRestoreCycle_Postgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	pg := startPostgres(t, ctx)
+	if err := seedPostgres(pg.URI); err != nil {
+		t.Fatalf("failed to seed postgres: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	archivePath, err := cmd.BackupPostgres(pg.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err != nil {
+		t.Fatalf("BackupPostgres failed: %v", err)
+	}
+
+	if err := cmd.VerifyBackup(archivePath); err != nil {
+		t.Fatalf("VerifyBackup rejected a good archive: %v", err)
+	}
+
+	if err := dropPostgresTable(pg.URI); err != nil {
+		t.Fatalf("failed to wipe postgres table: %v", err)
+	}
+
+	if err := cmd.RestorePostgres(pg.URI, archivePath); err != nil {
+		t.Fatalf("RestorePostgres failed: %v", err)
+	}
+
+	got, err := fetchPostgres(pg.URI)
+	if err != nil {
+		t.Fatalf("failed to fetch restored postgres data: %v", err)
+	}
+	if !reflect.DeepEqual(got, fixtureRows()) {
+		t.Fatalf("restored postgres data does not match fixture:\n got:  %+v\n want: %+v", got, fixtureRows())
+	}
+}
+
+// TestBackupUploadDownloadRoundTrip backs up Mongo, uploads the archive
+// to a real MinIO instance through the same StorageBackend restore uses,
+// downloads it back, and checks the bytes (and checksum) are unchanged.
+func TestBackupUploadDownloadRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	mongo := startMongo(t, ctx)
+	if err := seedMongo(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to seed mongo: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	archivePath, err := cmd.BackupMongo(mongo.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err != nil {
+		t.Fatalf("BackupMongo failed: %v", err)
+	}
+
+	minio := startMinio(t, ctx)
+	const bucket = "integration-test-backups"
+	if err := createBucket(minio, bucket); err != nil {
+		t.Fatalf("failed to create bucket: %v", err)
+	}
+	os.Setenv("MINIO_ACCESS_KEY", minio.AccessKey)
+	os.Setenv("MINIO_SECRET_KEY", minio.SecretKey)
+	defer os.Unsetenv("MINIO_ACCESS_KEY")
+	defer os.Unsetenv("MINIO_SECRET_KEY")
+
+	backend, err := cmd.NewStorageBackend("minio", bucket, "us-east-1", minio.Endpoint)
+	if err != nil {
+		t.Fatalf("NewStorageBackend failed: %v", err)
+	}
+
+	key := filepath.Base(archivePath)
+	if err := backend.Upload(ctx, archivePath, key); err != nil {
+		t.Fatalf("Upload failed: %v", err)
+	}
+
+	downloadPath := filepath.Join(outputDir, "downloaded.archive")
+	if err := backend.Download(ctx, key, downloadPath); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+
+	original, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("failed to read original archive: %v", err)
+	}
+	downloaded, err := os.ReadFile(downloadPath)
+	if err != nil {
+		t.Fatalf("failed to read downloaded archive: %v", err)
+	}
+	if !reflect.DeepEqual(original, downloaded) {
+		t.Fatal("downloaded archive does not match the uploaded one byte-for-byte")
+	}
+}
+
+// TestFailureInjection_TruncatedArchiveFailsVerification proves that a
+// backup cut short mid-write is rejected by VerifyBackup instead of
+// silently restoring a partial dataset.
+func TestFailureInjection_TruncatedArchiveFailsVerification(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	mongo := startMongo(t, ctx)
+	if err := seedMongo(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to seed mongo: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	archivePath, err := cmd.BackupMongo(mongo.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err != nil {
+		t.Fatalf("BackupMongo failed: %v", err)
+	}
+
+	truncated := archivePath + ".truncated"
+	if err := copyFile(archivePath, truncated); err != nil {
+		t.Fatalf("failed to copy archive: %v", err)
+	}
+	if err := copyFile(archivePath+".sha256", truncated+".sha256"); err != nil {
+		t.Fatalf("failed to copy checksum sidecar: %v", err)
+	}
+	if err := truncateFile(truncated, 256); err != nil {
+		t.Fatalf("failed to truncate archive: %v", err)
+	}
+
+	if err := cmd.VerifyBackup(truncated); err == nil {
+		t.Fatal("expected VerifyBackup to reject a truncated archive, got nil error")
+	}
+}
+
+// TestFailureInjection_CorruptedByteFailsVerification proves that a
+// single flipped byte anywhere in the archive - not just a short read at
+// the end - is also caught.
+func TestFailureInjection_CorruptedByteFailsVerification(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	mongo := startMongo(t, ctx)
+	if err := seedMongo(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to seed mongo: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	archivePath, err := cmd.BackupMongo(mongo.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err != nil {
+		t.Fatalf("BackupMongo failed: %v", err)
+	}
+
+	corrupted := archivePath + ".corrupted"
+	if err := copyFile(archivePath, corrupted); err != nil {
+		t.Fatalf("failed to copy archive: %v", err)
+	}
+	if err := copyFile(archivePath+".sha256", corrupted+".sha256"); err != nil {
+		t.Fatalf("failed to copy checksum sidecar: %v", err)
+	}
+	if err := corruptByte(corrupted, -1); err != nil {
+		t.Fatalf("failed to corrupt archive: %v", err)
+	}
+
+	if err := cmd.VerifyBackup(corrupted); err == nil {
+		t.Fatal("expected VerifyBackup to reject a corrupted archive, got nil error")
+	}
+}
+
+// TestFailureInjection_KillMidBackupReturnsError proves that killing the
+// source database partway through a backup surfaces as an error from
+// BackupMongo, rather than leaving behind a truncated archive that looks
+// like a successful backup.
+func TestFailureInjection_KillMidBackupReturnsError(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping container-backed integration test in short mode")
+	}
+	ctx := context.Background()
+
+	mongo := startMongo(t, ctx)
+	if err := seedMongo(ctx, mongo.URI); err != nil {
+		t.Fatalf("failed to seed mongo: %v", err)
+	}
+
+	// Kill the container shortly after the backup starts so mongodump is
+	// still mid-stream when the connection drops. The delay is a best
+	// effort, not a guarantee; a backup that completes before the kill
+	// fires would make this test vacuous rather than flaky, so its
+	// duration is generous relative to how long this fixture takes.
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		mongo.Kill(ctx)
+	}()
+
+	outputDir := t.TempDir()
+	_, err := cmd.BackupMongo(mongo.URI, cmd.BackupOptions{OutputDir: outputDir, Compress: true})
+	if err == nil {
+		t.Fatal("expected BackupMongo to return an error when the source database dies mid-backup")
+	}
+}
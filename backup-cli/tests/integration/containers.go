@@ -0,0 +1,184 @@
+// Package integration drives backup-cli's backup/restore entrypoints
+// against real, ephemeral MongoDB, PostgreSQL, and MinIO instances
+// instead of mocks, so a passing test actually proves mongodump/
+// mongorestore, pg_dump/psql, and the S3-compatible upload path work end
+// to end. Containers are started per test with testcontainers-go and
+// torn down in the test's Cleanup, so tests can run in parallel without
+// sharing state.
+//
+// These tests require a Docker daemon and are slow, so they're excluded
+// from the normal `go test ./...` run (see TestMain's testing.Short
+// check) and instead run via `make integration` / the dedicated CI job.
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// mongoContainer wraps a running MongoDB container and the URI to reach it.
+type mongoContainer struct {
+	container testcontainers.Container
+	URI       string
+}
+
+// startMongo launches a disposable MongoDB 6 container and returns it
+// once it's accepting connections. t.Cleanup terminates it when the test
+// ends, so callers don't need their own defer.
+func startMongo(t *testing.T, ctx context.Context) *mongoContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "mongo:6",
+		ExposedPorts: []string{"27017/tcp"},
+		WaitingFor:   wait.ForListeningPort("27017/tcp").WithStartupTimeout(60 * time.Second),
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start mongo container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Terminate(context.Background()); err != nil {
+			t.Logf("warning: failed to terminate mongo container: %v", err)
+		}
+	})
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get mongo container host: %v", err)
+	}
+	port, err := c.MappedPort(ctx, "27017")
+	if err != nil {
+		t.Fatalf("failed to get mongo container port: %v", err)
+	}
+
+	return &mongoContainer{
+		container: c,
+		URI:       fmt.Sprintf("mongodb://%s:%s/integration_test", host, port.Port()),
+	}
+}
+
+// Kill forcibly stops the container, simulating the database dying
+// mid-operation rather than shutting down cleanly.
+func (m *mongoContainer) Kill(ctx context.Context) error {
+	timeout := time.Duration(0)
+	return m.container.Stop(ctx, &timeout)
+}
+
+// postgresContainer wraps a running PostgreSQL container and the URI to
+// reach it.
+type postgresContainer struct {
+	container testcontainers.Container
+	URI       string
+}
+
+// startPostgres launches a disposable PostgreSQL 15 container seeded with
+// a fresh "integration_test" database and returns it once ready.
+func startPostgres(t *testing.T, ctx context.Context) *postgresContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "postgres:15",
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     "postgres",
+			"POSTGRES_PASSWORD": "postgres",
+			"POSTGRES_DB":       "integration_test",
+		},
+		WaitingFor: wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).WithStartupTimeout(60 * time.Second),
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Terminate(context.Background()); err != nil {
+			t.Logf("warning: failed to terminate postgres container: %v", err)
+		}
+	})
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get postgres container host: %v", err)
+	}
+	port, err := c.MappedPort(ctx, "5432")
+	if err != nil {
+		t.Fatalf("failed to get postgres container port: %v", err)
+	}
+
+	return &postgresContainer{
+		container: c,
+		URI:       fmt.Sprintf("postgres://postgres:postgres@%s:%s/integration_test?sslmode=disable", host, port.Port()),
+	}
+}
+
+// minioContainer wraps a running MinIO container exposing an
+// S3-compatible API.
+type minioContainer struct {
+	container testcontainers.Container
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+}
+
+const (
+	minioAccessKey = "integrationtest"
+	minioSecretKey = "integrationtestsecret"
+)
+
+// startMinio launches a disposable MinIO container in single-node mode
+// and returns it once its health endpoint responds.
+func startMinio(t *testing.T, ctx context.Context) *minioContainer {
+	t.Helper()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "minio/minio:latest",
+		ExposedPorts: []string{"9000/tcp"},
+		Cmd:          []string{"server", "/data"},
+		Env: map[string]string{
+			"MINIO_ROOT_USER":     minioAccessKey,
+			"MINIO_ROOT_PASSWORD": minioSecretKey,
+		},
+		WaitingFor: wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(60 * time.Second),
+	}
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		t.Fatalf("failed to start minio container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := c.Terminate(context.Background()); err != nil {
+			t.Logf("warning: failed to terminate minio container: %v", err)
+		}
+	})
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		t.Fatalf("failed to get minio container host: %v", err)
+	}
+	port, err := c.MappedPort(ctx, "9000")
+	if err != nil {
+		t.Fatalf("failed to get minio container port: %v", err)
+	}
+
+	return &minioContainer{
+		container: c,
+		Endpoint:  fmt.Sprintf("http://%s:%s", host, port.Port()),
+		AccessKey: minioAccessKey,
+		SecretKey: minioSecretKey,
+	}
+}
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const fixtureRowCount = 50
+
+// widgetFixture is the deterministic dataset seeded into both databases so
+// a restored copy can be diffed against it field by field. The values are
+// derived from i alone (no time.Now(), no randomness) so a test run is
+// reproducible and a diff failure is never a fixture artifact.
+type widgetFixture struct {
+	ID    int
+	Name  string
+	Value float64
+}
+
+func fixtureRows() []widgetFixture {
+	rows := make([]widgetFixture, fixtureRowCount)
+	for i := range rows {
+		rows[i] = widgetFixture{
+			ID:    i + 1,
+			Name:  fmt.Sprintf("widget-%03d", i+1),
+			Value: float64(i+1) * 1.5,
+		}
+	}
+	return rows
+}
+
+// seedMongo inserts the fixture dataset into the "widgets" collection of
+// the database named in uri.
+func seedMongo(ctx context.Context, uri string) error {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return fmt.Errorf("failed to connect to mongo: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	docs := make([]interface{}, 0, fixtureRowCount)
+	for _, row := range fixtureRows() {
+		docs = append(docs, bson.M{"_id": row.ID, "name": row.Name, "value": row.Value})
+	}
+
+	coll := client.Database(mongoDBName(uri)).Collection("widgets")
+	_, err = coll.InsertMany(ctx, docs)
+	return err
+}
+
+// fetchMongo reads the "widgets" collection back, sorted by _id so the
+// result can be compared directly against fixtureRows().
+func fetchMongo(ctx context.Context, uri string) ([]widgetFixture, error) {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mongo: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	coll := client.Database(mongoDBName(uri)).Collection("widgets")
+	cur, err := coll.Find(ctx, bson.M{}, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var rows []widgetFixture
+	for cur.Next(ctx) {
+		var doc struct {
+			ID    int     `bson:"_id"`
+			Name  string  `bson:"name"`
+			Value float64 `bson:"value"`
+		}
+		if err := cur.Decode(&doc); err != nil {
+			return nil, err
+		}
+		rows = append(rows, widgetFixture{ID: doc.ID, Name: doc.Name, Value: doc.Value})
+	}
+	return rows, cur.Err()
+}
+
+// dropMongoDatabase wipes uri's database so a restore starts from empty,
+// the same as a real disaster-recovery scenario.
+func dropMongoDatabase(ctx context.Context, uri string) error {
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(ctx)
+	return client.Database(mongoDBName(uri)).Drop(ctx)
+}
+
+func mongoDBName(uri string) string {
+	// uri is always built by startMongo as ".../integration_test", but stay
+	// honest about only supporting that one fixture database name.
+	return "integration_test"
+}
+
+// seedPostgres creates the "widgets" table and inserts the fixture
+// dataset.
+func seedPostgres(uri string) error {
+	db, err := sql.Open("postgres", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT NOT NULL, value NUMERIC NOT NULL)`); err != nil {
+		return fmt.Errorf("failed to create widgets table: %v", err)
+	}
+
+	for _, row := range fixtureRows() {
+		if _, err := db.Exec(`INSERT INTO widgets (id, name, value) VALUES ($1, $2, $3)`, row.ID, row.Name, row.Value); err != nil {
+			return fmt.Errorf("failed to insert fixture row %d: %v", row.ID, err)
+		}
+	}
+	return nil
+}
+
+// fetchPostgres reads the "widgets" table back, ordered by id.
+func fetchPostgres(uri string) ([]widgetFixture, error) {
+	db, err := sql.Open("postgres", uri)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	rows, err := db.Query(`SELECT id, name, value FROM widgets ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []widgetFixture
+	for rows.Next() {
+		var row widgetFixture
+		if err := rows.Scan(&row.ID, &row.Name, &row.Value); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// dropPostgresTable wipes the widgets table so a restore starts from
+// empty. pg_restore/psql will recreate it via the --clean --if-exists
+// --create dump, so the table doesn't need to exist afterward either.
+func dropPostgresTable(uri string) error {
+	db, err := sql.Open("postgres", uri)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`DROP TABLE IF EXISTS widgets`)
+	return err
+}
+
+// createBucket creates bucket on the S3-compatible endpoint (MinIO
+// doesn't auto-create buckets the way some object stores do).
+func createBucket(m *minioContainer, bucket string) error {
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String("us-east-1"),
+		Endpoint:         aws.String(m.Endpoint),
+		S3ForcePathStyle: aws.Bool(true),
+		Credentials:      credentials.NewStaticCredentials(m.AccessKey, m.SecretKey, ""),
+	})
+	if err != nil {
+		return err
+	}
+	svc := s3.New(sess)
+	_, err = svc.CreateBucket(&s3.CreateBucketInput{Bucket: aws.String(bucket)})
+	return err
+}
@@ -0,0 +1,42 @@
+package integration
+
+import "os"
+
+// copyFile duplicates src to dst (including its .sha256 sidecar, if any)
+// so a failure-injection test can tamper with the copy and leave the
+// original backup alone.
+func copyFile(src, dst string) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+// truncateFile drops the last n bytes of path, simulating an archive that
+// was cut short mid-write - the known mongodump-style failure mode where
+// a short archive can make a naive restore hang instead of erroring.
+func truncateFile(path string, n int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if n > len(data) {
+		n = len(data)
+	}
+	return os.WriteFile(path, data[:len(data)-n], 0644)
+}
+
+// corruptByte flips one bit at offset, simulating bitrot or a partial
+// write landing in the middle of the archive rather than at the end.
+func corruptByte(path string, offset int) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if offset < 0 || offset >= len(data) {
+		offset = len(data) / 2
+	}
+	data[offset] ^= 0xFF
+	return os.WriteFile(path, data, 0644)
+}
@@ -1,93 +1,17 @@
+// Package tests holds the lightweight, no-external-dependencies tests
+// that run as part of the normal unit test suite. The real backup/
+// restore round trips - against live MongoDB, PostgreSQL and MinIO
+// containers - live in tests/integration instead, since they need Docker
+// and are wired into `make integration` and a separate CI job rather than
+// the default `go test ./...`.
 package tests
 
 import (
 	"os"
 	"path/filepath"
 	"testing"
-	"time"
-
-	"backup-cli/cmd"
 )
 
-func TestBackupIntegration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
-
-	// Setup test environment
-	testDir, err := os.MkdirTemp("", "backup-test-*")
-	if err != nil {
-		t.Fatalf("Failed to create test directory: %v", err)
-	}
-	defer os.RemoveAll(testDir)
-
-	// Test MongoDB backup (requires running MongoDB)
-	t.Run("MongoDB Backup", func(t *testing.T) {
-		mongoURI := os.Getenv("TEST_MONGO_URI")
-		if mongoURI == "" {
-			t.Skip("TEST_MONGO_URI not set, skipping MongoDB test")
-		}
-
-		// Set up test parameters
-		outputDir := filepath.Join(testDir, "mongo")
-		os.MkdirAll(outputDir, 0755)
-
-		// TODO: Add actual MongoDB backup test
-		// This would require setting up test data and verifying backup creation
-		t.Log("MongoDB backup test would run here with live database")
-	})
-
-	// Test PostgreSQL backup (requires running PostgreSQL)
-	t.Run("PostgreSQL Backup", func(t *testing.T) {
-		pgURI := os.Getenv("TEST_POSTGRES_URI")
-		if pgURI == "" {
-			t.Skip("TEST_POSTGRES_URI not set, skipping PostgreSQL test")
-		}
-
-		// Set up test parameters
-		outputDir := filepath.Join(testDir, "postgres")
-		os.MkdirAll(outputDir, 0755)
-
-		// TODO: Add actual PostgreSQL backup test
-		t.Log("PostgreSQL backup test would run here with live database")
-	})
-}
-
-func TestBackupRestoreCycle(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
-
-	// This test would:
-	// 1. Create test data in a database
-	// 2. Run backup
-	// 3. Clear the database
-	// 4. Run restore
-	// 5. Verify data is restored correctly
-
-	t.Log("Full backup/restore cycle test would run here")
-}
-
-func TestS3Integration(t *testing.T) {
-	if testing.Short() {
-		t.Skip("Skipping integration test in short mode")
-	}
-
-	s3Bucket := os.Getenv("TEST_S3_BUCKET")
-	if s3Bucket == "" {
-		t.Skip("TEST_S3_BUCKET not set, skipping S3 test")
-	}
-
-	// This test would:
-	// 1. Create a test backup file
-	// 2. Upload to S3
-	// 3. Download from S3
-	// 4. Verify integrity
-	// 5. Clean up
-
-	t.Log("S3 integration test would run here")
-}
-
 func TestChecksumVerification(t *testing.T) {
 	testDir, err := os.MkdirTemp("", "checksum-test-*")
 	if err != nil {
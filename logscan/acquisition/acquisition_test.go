@@ -0,0 +1,125 @@
+package acquisition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBadConfiguration checks that every module's Configure rejects a
+// source entry missing its required fields, instead of failing later at
+// Start (or worse, silently no-op'ing).
+func TestBadConfiguration(t *testing.T) {
+	tests := []struct {
+		name string
+		src  LogSource
+		cfg  map[string]interface{}
+	}{
+		{"file missing path", &FileSource{}, map[string]interface{}{"type": "file"}},
+		{"syslog missing address", &SyslogSource{}, map[string]interface{}{"type": "syslog", "protocol": "udp"}},
+		{"syslog bad protocol", &SyslogSource{}, map[string]interface{}{"type": "syslog", "protocol": "sctp", "address": "0.0.0.0:5514"}},
+		{"kafka missing topic", &KafkaSource{}, map[string]interface{}{"type": "kafka", "brokers": []interface{}{"kafka:9092"}}},
+		{"kafka missing brokers", &KafkaSource{}, map[string]interface{}{"type": "kafka", "topic": "logs"}},
+		{"http missing address", &HTTPSource{}, map[string]interface{}{"type": "http"}},
+		{"http bad path", &HTTPSource{}, map[string]interface{}{"type": "http", "address": "0.0.0.0:8088", "path": "logs"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.src.Configure(tc.cfg); err == nil {
+				t.Errorf("Configure(%v) should have failed validation", tc.cfg)
+			}
+		})
+	}
+}
+
+// TestGoodConfiguration is TestBadConfiguration's complement: a source
+// entry with every required field should configure cleanly and report the
+// name it was given.
+func TestGoodConfiguration(t *testing.T) {
+	tests := []struct {
+		name string
+		src  LogSource
+		cfg  map[string]interface{}
+	}{
+		{"file", &FileSource{}, map[string]interface{}{"type": "file", "name": "app-log", "path": "/var/log/app.jsonl"}},
+		{"syslog", &SyslogSource{}, map[string]interface{}{"type": "syslog", "name": "net-syslog", "protocol": "tcp", "address": "0.0.0.0:5514"}},
+		{"journald", &JournaldSource{}, map[string]interface{}{"type": "journald", "name": "journal"}},
+		{"kafka", &KafkaSource{}, map[string]interface{}{"type": "kafka", "name": "events", "brokers": []interface{}{"kafka:9092"}, "topic": "app-logs"}},
+		{"http", &HTTPSource{}, map[string]interface{}{"type": "http", "name": "push", "address": "0.0.0.0:8088", "path": "/logs"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.src.Configure(tc.cfg); err != nil {
+				t.Fatalf("Configure(%v) failed: %v", tc.cfg, err)
+			}
+			if got := tc.src.Name(); got != tc.cfg["name"] {
+				t.Errorf("Name() = %q, want %q", got, tc.cfg["name"])
+			}
+		})
+	}
+}
+
+// TestNewUnknownType checks New rejects a source type not in the registry.
+func TestNewUnknownType(t *testing.T) {
+	if _, err := New(map[string]interface{}{"type": "carrier-pigeon"}); err == nil {
+		t.Error("New should reject an unregistered source type")
+	}
+}
+
+// TestLoadConfig checks a sources: YAML document round-trips into Config
+// and that a source missing "type" is rejected up front.
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sources.yaml")
+	yamlDoc := `
+sources:
+  - type: file
+    name: app-log
+    path: /var/log/app.jsonl
+  - type: syslog
+    name: net-syslog
+    protocol: udp
+    address: 0.0.0.0:5514
+`
+	if err := os.WriteFile(path, []byte(yamlDoc), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if len(cfg.Sources) != 2 {
+		t.Fatalf("expected 2 sources, got %d", len(cfg.Sources))
+	}
+	if cfg.Sources[0]["type"] != "file" {
+		t.Errorf("sources[0].type = %v, want file", cfg.Sources[0]["type"])
+	}
+
+	sources, err := NewAll(cfg)
+	if err != nil {
+		t.Fatalf("NewAll failed: %v", err)
+	}
+	if len(sources) != 2 {
+		t.Fatalf("expected 2 configured sources, got %d", len(sources))
+	}
+}
+
+func TestLoadConfigMissingType(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sources.yaml")
+	yamlDoc := `
+sources:
+  - name: app-log
+    path: /var/log/app.jsonl
+`
+	if err := os.WriteFile(path, []byte(yamlDoc), 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	if _, err := LoadConfig(path); err == nil {
+		t.Error("LoadConfig should reject a source entry missing \"type\"")
+	}
+}
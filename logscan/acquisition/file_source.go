@@ -0,0 +1,132 @@
+package acquisition
+
+import (
+	"io"
+	"log"
+	"path/filepath"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileSource tails a log file, modeled on CrowdSec's file acquisition
+// module: instead of --tail's poll loop (source/reader.go's
+// tailPollInterval), it blocks on an fsnotify watch and only wakes up to
+// read when the file is actually written to or rotated.
+//
+//	type: file
+//	path: /var/log/app/current.jsonl
+//	format: jsonl   # optional, auto-detected like --format-in
+type FileSource struct {
+	name   string
+	path   string
+	format source.Format
+
+	watcher *fsnotify.Watcher
+	src     source.Source
+	stop    chan struct{}
+}
+
+func (f *FileSource) Configure(cfg map[string]interface{}) error {
+	path, err := stringField(cfg, "path")
+	if err != nil {
+		return err
+	}
+	f.name = nameOrType(cfg)
+	f.path = path
+	f.format = source.Format(stringFieldDefault(cfg, "format", ""))
+	return nil
+}
+
+func (f *FileSource) Name() string { return f.name }
+
+func (f *FileSource) Start(out chan<- source.LogEntry) error {
+	src, err := source.Open(f.path, f.format, false)
+	if err != nil {
+		return err
+	}
+	f.src = src
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		src.Close()
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(f.path)); err != nil {
+		watcher.Close()
+		src.Close()
+		return err
+	}
+	f.watcher = watcher
+	f.stop = make(chan struct{})
+
+	go f.run(out)
+	return nil
+}
+
+// run drains whatever source.Open already decoded, then blocks on fsnotify
+// events for f.path (writes append more to decode, a create/rename is
+// treated as log rotation and the file is reopened from the top) until
+// Stop closes f.stop.
+func (f *FileSource) run(out chan<- source.LogEntry) {
+	defer close(out)
+	defer f.watcher.Close()
+	defer f.src.Close()
+
+	drain := func() bool {
+		for {
+			entry, err := f.src.Next()
+			if err == io.EOF {
+				return true
+			}
+			if err != nil {
+				log.Printf("Warning: file source %s: failed to decode line: %v", f.name, err)
+				continue
+			}
+			out <- entry
+		}
+	}
+	drain()
+
+	for {
+		select {
+		case <-f.stop:
+			return
+		case event, ok := <-f.watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != filepath.Clean(f.path) {
+				continue
+			}
+			if event.Op&(fsnotify.Create|fsnotify.Rename) != 0 {
+				f.reopen()
+			}
+			drain()
+		case err, ok := <-f.watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Printf("Warning: file source %s: watch error: %v", f.name, err)
+		}
+	}
+}
+
+// reopen replaces f.src after a rotation (the old file was renamed/removed
+// and a new one created at f.path).
+func (f *FileSource) reopen() {
+	src, err := source.Open(f.path, f.format, false)
+	if err != nil {
+		log.Printf("Warning: file source %s: failed to reopen after rotation: %v", f.name, err)
+		return
+	}
+	f.src.Close()
+	f.src = src
+}
+
+func (f *FileSource) Stop() error {
+	if f.stop != nil {
+		close(f.stop)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package acquisition
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// HTTPSource runs an HTTP server that accepts NDJSON-encoded LogEntry
+// values via POST, for agents that push logs rather than being tailed or
+// polled.
+//
+//	type: http
+//	address: 0.0.0.0:8088
+//	path: /logs   # optional, defaults to /logs
+type HTTPSource struct {
+	name    string
+	address string
+	path    string
+
+	server *http.Server
+}
+
+func (h *HTTPSource) Configure(cfg map[string]interface{}) error {
+	address, err := stringField(cfg, "address")
+	if err != nil {
+		return err
+	}
+	h.name = nameOrType(cfg)
+	h.address = address
+	h.path = stringFieldDefault(cfg, "path", "/logs")
+	if !strings.HasPrefix(h.path, "/") {
+		return fmt.Errorf("field \"path\" must start with \"/\", got %q", h.path)
+	}
+	return nil
+}
+
+func (h *HTTPSource) Name() string { return h.name }
+
+func (h *HTTPSource) Start(out chan<- source.LogEntry) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(h.path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST required", http.StatusMethodNotAllowed)
+			return
+		}
+		defer r.Body.Close()
+
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		var decoded int
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var entry source.LogEntry
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				http.Error(w, fmt.Sprintf("invalid NDJSON line: %v", err), http.StatusBadRequest)
+				return
+			}
+			out <- entry
+			decoded++
+		}
+		if err := scanner.Err(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "{\"accepted\":%d}\n", decoded)
+	})
+
+	h.server = &http.Server{Addr: h.address, Handler: mux}
+
+	ln, err := net.Listen("tcp", h.address)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(out)
+		if err := h.server.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: http source %s: server stopped: %v", h.name, err)
+		}
+	}()
+	return nil
+}
+
+func (h *HTTPSource) Stop() error {
+	if h.server == nil {
+		return nil
+	}
+	return h.server.Shutdown(context.Background())
+}
@@ -0,0 +1,131 @@
+package acquisition
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// JournaldSource follows the systemd journal via `journalctl -f -o json`,
+// the same shell-out-to-the-platform-tool approach backup-cli uses for
+// pg_dump/mongodump rather than linking against libsystemd.
+//
+//	type: journald
+//	unit: myapp.service   # optional; unset follows the whole journal
+type JournaldSource struct {
+	name string
+	unit string
+
+	cmd  *exec.Cmd
+	stop chan struct{}
+}
+
+func (j *JournaldSource) Configure(cfg map[string]interface{}) error {
+	j.name = nameOrType(cfg)
+	j.unit = stringFieldDefault(cfg, "unit", "")
+	return nil
+}
+
+func (j *JournaldSource) Name() string { return j.name }
+
+func (j *JournaldSource) Start(out chan<- source.LogEntry) error {
+	args := []string{"-f", "-o", "json", "--no-pager"}
+	if j.unit != "" {
+		args = append(args, "-u", j.unit)
+	}
+	cmd := exec.Command("journalctl", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to attach to journalctl stdout: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start journalctl: %v", err)
+	}
+
+	j.cmd = cmd
+	j.stop = make(chan struct{})
+	go j.run(stdout, out)
+	return nil
+}
+
+// journalEntry mirrors the fields of systemd's journal export JSON format
+// (man systemd.journal-fields) that map onto a LogEntry.
+type journalEntry struct {
+	RealtimeTimestamp string `json:"__REALTIME_TIMESTAMP"`
+	Message           string `json:"MESSAGE"`
+	Priority          string `json:"PRIORITY"`
+	SyslogIdentifier  string `json:"SYSLOG_IDENTIFIER"`
+	Unit              string `json:"_SYSTEMD_UNIT"`
+}
+
+func (j *JournaldSource) run(stdout io.Reader, out chan<- source.LogEntry) {
+	defer close(out)
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var je journalEntry
+		if err := json.Unmarshal([]byte(line), &je); err != nil {
+			log.Printf("Warning: journald source %s: failed to decode entry: %v", j.name, err)
+			continue
+		}
+		out <- journalEntryToLogEntry(je)
+	}
+
+	select {
+	case <-j.stop:
+	default:
+		if err := scanner.Err(); err != nil {
+			log.Printf("Warning: journald source %s: journalctl stream ended: %v", j.name, err)
+		}
+	}
+}
+
+func journalEntryToLogEntry(je journalEntry) source.LogEntry {
+	timestamp := time.Now().Format(time.RFC3339)
+	if usec, err := strconv.ParseInt(je.RealtimeTimestamp, 10, 64); err == nil {
+		timestamp = time.UnixMicro(usec).UTC().Format(time.RFC3339)
+	}
+
+	level := "INFO"
+	if prio, err := strconv.Atoi(je.Priority); err == nil {
+		switch {
+		case prio <= 3:
+			level = "ERROR"
+		case prio == 4:
+			level = "WARN"
+		}
+	}
+
+	return source.LogEntry{
+		Timestamp: timestamp,
+		Level:     level,
+		Message:   je.Message,
+		Action:    je.SyslogIdentifier,
+		Extra: map[string]interface{}{
+			"unit": je.Unit,
+		},
+	}
+}
+
+func (j *JournaldSource) Stop() error {
+	if j.stop != nil {
+		close(j.stop)
+	}
+	if j.cmd != nil && j.cmd.Process != nil {
+		return j.cmd.Process.Kill()
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package acquisition
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// KafkaSource consumes NDJSON-encoded LogEntry messages from a Kafka
+// topic.
+//
+//	type: kafka
+//	brokers: [kafka-1:9092, kafka-2:9092]
+//	topic: app-logs
+//	group_id: logscan   # optional, defaults to "logscan"
+type KafkaSource struct {
+	name    string
+	brokers []string
+	topic   string
+	groupID string
+
+	reader *kafka.Reader
+	cancel context.CancelFunc
+}
+
+func (k *KafkaSource) Configure(cfg map[string]interface{}) error {
+	topic, err := stringField(cfg, "topic")
+	if err != nil {
+		return err
+	}
+
+	var brokers []string
+	switch v := cfg["brokers"].(type) {
+	case []interface{}:
+		for _, b := range v {
+			s, ok := b.(string)
+			if !ok || s == "" {
+				return fmt.Errorf("field \"brokers\" must be a list of non-empty strings")
+			}
+			brokers = append(brokers, s)
+		}
+	case string:
+		brokers = strings.Split(v, ",")
+	default:
+		return fmt.Errorf("missing required field \"brokers\" (list of host:port strings)")
+	}
+	if len(brokers) == 0 {
+		return fmt.Errorf("field \"brokers\" must not be empty")
+	}
+
+	k.name = nameOrType(cfg)
+	k.brokers = brokers
+	k.topic = topic
+	k.groupID = stringFieldDefault(cfg, "group_id", "logscan")
+	return nil
+}
+
+func (k *KafkaSource) Name() string { return k.name }
+
+func (k *KafkaSource) Start(out chan<- source.LogEntry) error {
+	k.reader = kafka.NewReader(kafka.ReaderConfig{
+		Brokers: k.brokers,
+		Topic:   k.topic,
+		GroupID: k.groupID,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	k.cancel = cancel
+	go k.run(ctx, out)
+	return nil
+}
+
+func (k *KafkaSource) run(ctx context.Context, out chan<- source.LogEntry) {
+	defer close(out)
+	defer k.reader.Close()
+
+	for {
+		msg, err := k.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Warning: kafka source %s: read failed: %v", k.name, err)
+			return
+		}
+
+		var entry source.LogEntry
+		if err := json.Unmarshal(msg.Value, &entry); err != nil {
+			log.Printf("Warning: kafka source %s: failed to decode message at offset %d: %v", k.name, msg.Offset, err)
+			continue
+		}
+		out <- entry
+	}
+}
+
+func (k *KafkaSource) Stop() error {
+	if k.cancel != nil {
+		k.cancel()
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package acquisition
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// rfc5424Pattern matches an RFC 5424 syslog message:
+//
+//	<PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+//
+// e.g. "<34>1 2024-01-05T22:14:15.003Z host.example.org app 1234 ID47 - message text"
+var rfc5424Pattern = regexp.MustCompile(`^<(\d{1,3})>(\d)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(\S+)\s+(?:\[.*?\]|-)\s*(.*)$`)
+
+// SyslogSource listens for RFC 5424 syslog messages over UDP or TCP.
+//
+//	type: syslog
+//	protocol: udp   # or tcp
+//	address: 0.0.0.0:5514
+type SyslogSource struct {
+	name     string
+	protocol string
+	address  string
+
+	udpConn  net.PacketConn
+	tcpLn    net.Listener
+	stop     chan struct{}
+}
+
+func (s *SyslogSource) Configure(cfg map[string]interface{}) error {
+	protocol := strings.ToLower(stringFieldDefault(cfg, "protocol", "udp"))
+	if protocol != "udp" && protocol != "tcp" {
+		return fmt.Errorf("protocol must be \"udp\" or \"tcp\", got %q", protocol)
+	}
+	address, err := stringField(cfg, "address")
+	if err != nil {
+		return err
+	}
+
+	s.name = nameOrType(cfg)
+	s.protocol = protocol
+	s.address = address
+	return nil
+}
+
+func (s *SyslogSource) Name() string { return s.name }
+
+func (s *SyslogSource) Start(out chan<- source.LogEntry) error {
+	s.stop = make(chan struct{})
+
+	if s.protocol == "udp" {
+		conn, err := net.ListenPacket("udp", s.address)
+		if err != nil {
+			return err
+		}
+		s.udpConn = conn
+		go s.runUDP(out)
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.tcpLn = ln
+	go s.runTCP(out)
+	return nil
+}
+
+func (s *SyslogSource) runUDP(out chan<- source.LogEntry) {
+	defer close(out)
+	buf := make([]byte, 64*1024)
+	for {
+		n, _, err := s.udpConn.ReadFrom(buf)
+		if err != nil {
+			select {
+			case <-s.stop:
+				return
+			default:
+				log.Printf("Warning: syslog source %s: udp read failed: %v", s.name, err)
+				return
+			}
+		}
+		s.parseAndEmit(string(buf[:n]), out)
+	}
+}
+
+func (s *SyslogSource) runTCP(out chan<- source.LogEntry) {
+	defer close(out)
+	for {
+		conn, err := s.tcpLn.Accept()
+		if err != nil {
+			select {
+			case <-s.stop:
+				return
+			default:
+				log.Printf("Warning: syslog source %s: tcp accept failed: %v", s.name, err)
+				return
+			}
+		}
+		go s.handleTCPConn(conn, out)
+	}
+}
+
+func (s *SyslogSource) handleTCPConn(conn net.Conn, out chan<- source.LogEntry) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		s.parseAndEmit(scanner.Text(), out)
+	}
+}
+
+func (s *SyslogSource) parseAndEmit(line string, out chan<- source.LogEntry) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+	entry, err := parseRFC5424(line)
+	if err != nil {
+		log.Printf("Warning: syslog source %s: %v", s.name, err)
+		return
+	}
+	out <- entry
+}
+
+func parseRFC5424(line string) (source.LogEntry, error) {
+	m := rfc5424Pattern.FindStringSubmatch(line)
+	if m == nil {
+		return source.LogEntry{}, fmt.Errorf("failed to parse RFC 5424 message: %q", line)
+	}
+
+	pri, _ := strconv.Atoi(m[1])
+	severity := pri % 8
+	level := "INFO"
+	switch {
+	case severity <= 3:
+		level = "ERROR"
+	case severity == 4:
+		level = "WARN"
+	}
+
+	timestamp := m[3]
+	if ts, err := time.Parse(time.RFC3339Nano, m[3]); err == nil {
+		timestamp = ts.Format(time.RFC3339)
+	}
+
+	return source.LogEntry{
+		Timestamp: timestamp,
+		Level:     level,
+		Message:   m[8],
+		Action:    m[5], // APP-NAME
+		Extra: map[string]interface{}{
+			"host":    m[4],
+			"procid":  m[6],
+			"msgid":   m[7],
+			"pri":     pri,
+		},
+	}, nil
+}
+
+func (s *SyslogSource) Stop() error {
+	if s.stop != nil {
+		close(s.stop)
+	}
+	if s.udpConn != nil {
+		return s.udpConn.Close()
+	}
+	if s.tcpLn != nil {
+		return s.tcpLn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/baseline"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/rules"
+)
+
+// adaptiveEngine runs the --rules EWMA-based detector described in
+// newAdaptiveEngine's callers: it buckets matching entries per
+// (rule, group value) and feeds each bucket's count through that key's
+// baseline.Tracker as soon as the bucket closes (a later entry arrives in
+// a later bucket), so it works the same way whether it's fed a sorted
+// batch of entries or a live --tail stream.
+type adaptiveEngine struct {
+	ruleSet []rules.Rule
+	store   *baseline.Store
+
+	open      map[string]*adaptiveBucket
+	lastAlert map[string]time.Time
+}
+
+type adaptiveBucket struct {
+	rule    rules.Rule
+	key     string
+	start   time.Time
+	count   int
+	entries []LogEntry
+}
+
+func newAdaptiveEngine(ruleSet []rules.Rule, store *baseline.Store) *adaptiveEngine {
+	return &adaptiveEngine{
+		ruleSet:   ruleSet,
+		store:     store,
+		open:      make(map[string]*adaptiveBucket),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// Feed records entry against every rule it matches and returns any
+// anomalies produced by a bucket that closed as a result (i.e. a later
+// entry for that rule+key arrived in the next window).
+func (e *adaptiveEngine) Feed(entry LogEntry) []AnomalyResult {
+	t, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		return nil
+	}
+
+	var found []AnomalyResult
+	for _, rule := range e.ruleSet {
+		if !rule.Matches(entry) {
+			continue
+		}
+
+		value := rule.GroupValue(entry)
+		if value == "" {
+			continue
+		}
+		key := rule.Name + "|" + value
+		bucketStart := t.Truncate(rule.Window)
+
+		b := e.open[key]
+		if b != nil && !b.start.Equal(bucketStart) {
+			if a := e.closeBucket(b); a != nil {
+				found = append(found, *a)
+			}
+			b = nil
+		}
+		if b == nil {
+			b = &adaptiveBucket{rule: rule, key: key, start: bucketStart}
+			e.open[key] = b
+		}
+		b.count++
+		b.entries = append(b.entries, entry)
+	}
+
+	return found
+}
+
+// FlushAll closes every still-open bucket and returns any anomalies that
+// produces. Call it once a batch of entries is exhausted (there's no more
+// data coming that could close a bucket naturally); a --tail run has no
+// equivalent end, so its trailing bucket is simply left open until the
+// next entry closes it.
+func (e *adaptiveEngine) FlushAll() []AnomalyResult {
+	var found []AnomalyResult
+	for key, b := range e.open {
+		if a := e.closeBucket(b); a != nil {
+			found = append(found, *a)
+		}
+		delete(e.open, key)
+	}
+	return found
+}
+
+func (e *adaptiveEngine) closeBucket(b *adaptiveBucket) *AnomalyResult {
+	tracker := e.store.Tracker(b.key)
+	baselineMean := tracker.Mean
+	anomalous, z := tracker.Observe(float64(b.count), b.rule.Alpha, b.rule.K, b.rule.Warmup)
+	if !anomalous {
+		return nil
+	}
+	if last, ok := e.lastAlert[b.key]; ok && b.start.Sub(last) < b.rule.Cooldown {
+		return nil
+	}
+	e.lastAlert[b.key] = b.start
+
+	return &AnomalyResult{
+		Type:        "adaptive_" + b.rule.Name,
+		Description: fmt.Sprintf("%s: %d events in %s (z=%.2f, baseline mean=%.2f)", b.key, b.count, b.rule.Window, z, baselineMean),
+		Count:       b.count,
+		TimeWindow:  b.rule.Window.String(),
+		FirstSeen:   b.start,
+		LastSeen:    b.start.Add(b.rule.Window),
+		Entries:     limitEntries(b.entries, 10),
+		Extra: map[string]interface{}{
+			"rule":          b.rule.Name,
+			"z_score":       z,
+			"baseline_mean": baselineMean,
+		},
+	}
+}
+
+// runAdaptiveDetection loads --rules and, optionally, an existing
+// --baseline-file, then feeds entries (which must already be in
+// chronological order) through an adaptiveEngine and returns whatever it
+// flagged.
+func runAdaptiveDetection(entries []LogEntry) ([]AnomalyResult, error) {
+	ruleSet, err := rules.Load(rulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	store := baseline.NewStore()
+	if baselineFile != "" {
+		store, err = baseline.LoadStore(baselineFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := append([]LogEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	engine := newAdaptiveEngine(ruleSet, store)
+	var anomalies []AnomalyResult
+	for _, entry := range sorted {
+		anomalies = append(anomalies, engine.Feed(entry)...)
+	}
+	anomalies = append(anomalies, engine.FlushAll()...)
+
+	return anomalies, nil
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/enrich"
+)
+
+// Windows bound how long streamAnomalyEngine retains state for each
+// detector, so a --tail session's memory use tracks recent activity
+// instead of growing with how long it has been running.
+const (
+	loginBurstWindow = 5 * time.Minute
+	exportWindow     = 24 * time.Hour
+	apiAccessWindow  = 1 * time.Hour
+	ipAnomalyWindow  = 24 * time.Hour
+)
+
+// streamAnomalyEngine detects the same anomaly classes as
+// detectSecurityAnomalies, but incrementally: Feed is called once per entry
+// as it arrives from a --tail source. Each detector keeps only the state
+// inside its own time window, trimming anything older as new entries
+// arrive, and reports a given key only once to avoid repeating the same
+// alert for every subsequent entry that still satisfies it.
+type streamAnomalyEngine struct {
+	loginFailures map[string]map[string][]LogEntry // user -> 5-min bucket -> failures
+	loginReported map[string]bool                  // "user|bucket" already reported
+
+	exports         map[string][]LogEntry // user -> recent export entries
+	exportsReported map[string]bool
+
+	requests         map[string][]LogEntry // user -> recent requests
+	requestsReported map[string]bool
+
+	ipUsers    map[string]map[string]LogEntry // ip -> user -> most recent entry
+	ipReported map[string]bool
+
+	lastGeo map[string]LogEntry // user -> most recent geo-enriched entry
+
+	maliciousEntries  map[string][]LogEntry // ip -> entries matching a --threat-feed
+	maliciousFeed     map[string]string     // ip -> matching threat feed name
+	maliciousReported map[string]bool
+}
+
+func newStreamAnomalyEngine() *streamAnomalyEngine {
+	return &streamAnomalyEngine{
+		loginFailures:     make(map[string]map[string][]LogEntry),
+		loginReported:     make(map[string]bool),
+		exports:           make(map[string][]LogEntry),
+		exportsReported:   make(map[string]bool),
+		requests:          make(map[string][]LogEntry),
+		requestsReported:  make(map[string]bool),
+		ipUsers:           make(map[string]map[string]LogEntry),
+		ipReported:        make(map[string]bool),
+		lastGeo:           make(map[string]LogEntry),
+		maliciousEntries:  make(map[string][]LogEntry),
+		maliciousFeed:     make(map[string]string),
+		maliciousReported: make(map[string]bool),
+	}
+}
+
+// Feed records entry and returns any anomalies that newly cross a
+// detection threshold as a result of it.
+func (e *streamAnomalyEngine) Feed(entry LogEntry) []AnomalyResult {
+	var found []AnomalyResult
+
+	entryTime, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		return found
+	}
+
+	if entry.Action == "login" && entry.Status >= 400 {
+		if a := e.feedLoginFailure(entry, entryTime); a != nil {
+			found = append(found, *a)
+		}
+	}
+
+	if strings.Contains(strings.ToLower(entry.Action), "export") ||
+		strings.Contains(strings.ToLower(entry.Endpoint), "export") ||
+		strings.Contains(strings.ToLower(entry.Message), "export") {
+		if a := e.feedExport(entry, entryTime); a != nil {
+			found = append(found, *a)
+		}
+	}
+
+	if entry.Endpoint != "" {
+		if a := e.feedRequest(entry, entryTime); a != nil {
+			found = append(found, *a)
+		}
+	}
+
+	if entry.IP != "" && entry.UserID != "" {
+		if a := e.feedIP(entry, entryTime); a != nil {
+			found = append(found, *a)
+		}
+	}
+
+	if entry.IP != "" && entry.UserID != "" {
+		if _, ok := entry.Extra["geo_lat"]; ok {
+			if a := e.feedImpossibleTravel(entry, entryTime); a != nil {
+				found = append(found, *a)
+			}
+		}
+	}
+
+	if entry.IP != "" && entry.Extra["threat_match"] == true {
+		if a := e.feedKnownMaliciousIP(entry, entryTime); a != nil {
+			found = append(found, *a)
+		}
+	}
+
+	return found
+}
+
+func (e *streamAnomalyEngine) feedLoginFailure(entry LogEntry, t time.Time) *AnomalyResult {
+	bucketTime := t.Truncate(loginBurstWindow)
+	bucket := bucketTime.Format(time.RFC3339)
+
+	if e.loginFailures[entry.UserID] == nil {
+		e.loginFailures[entry.UserID] = make(map[string][]LogEntry)
+	}
+	e.loginFailures[entry.UserID][bucket] = append(e.loginFailures[entry.UserID][bucket], entry)
+
+	for b := range e.loginFailures[entry.UserID] {
+		if bt, err := time.Parse(time.RFC3339, b); err == nil && t.Sub(bt) > 2*loginBurstWindow {
+			delete(e.loginFailures[entry.UserID], b)
+			delete(e.loginReported, entry.UserID+"|"+b)
+		}
+	}
+
+	failures := e.loginFailures[entry.UserID][bucket]
+	key := entry.UserID + "|" + bucket
+	if len(failures) < 5 || e.loginReported[key] {
+		return nil
+	}
+	e.loginReported[key] = true
+
+	return &AnomalyResult{
+		Type:        "failed_login_burst",
+		Description: fmt.Sprintf("User %s had %d failed login attempts in 5 minutes", entry.UserID, len(failures)),
+		Count:       len(failures),
+		TimeWindow:  "5 minutes",
+		FirstSeen:   bucketTime,
+		LastSeen:    bucketTime.Add(loginBurstWindow),
+		Entries:     append([]LogEntry(nil), failures...),
+	}
+}
+
+func (e *streamAnomalyEngine) feedExport(entry LogEntry, t time.Time) *AnomalyResult {
+	e.exports[entry.UserID] = trimOlderThan(append(e.exports[entry.UserID], entry), t, exportWindow)
+	exports := e.exports[entry.UserID]
+
+	if len(exports) <= 10 || e.exportsReported[entry.UserID] {
+		return nil
+	}
+	e.exportsReported[entry.UserID] = true
+
+	first, _ := time.Parse(time.RFC3339, exports[0].Timestamp)
+	last, _ := time.Parse(time.RFC3339, exports[len(exports)-1].Timestamp)
+
+	return &AnomalyResult{
+		Type:        "data_export_spike",
+		Description: fmt.Sprintf("User %s performed %d data exports", entry.UserID, len(exports)),
+		Count:       len(exports),
+		TimeWindow:  fmt.Sprintf("%.1f hours", last.Sub(first).Hours()),
+		FirstSeen:   first,
+		LastSeen:    last,
+		Entries:     limitEntries(exports, 10),
+	}
+}
+
+func (e *streamAnomalyEngine) feedRequest(entry LogEntry, t time.Time) *AnomalyResult {
+	e.requests[entry.UserID] = trimOlderThan(append(e.requests[entry.UserID], entry), t, apiAccessWindow)
+	requests := e.requests[entry.UserID]
+
+	if len(requests) <= 100 || e.requestsReported[entry.UserID] {
+		return nil
+	}
+
+	first, _ := time.Parse(time.RFC3339, requests[0].Timestamp)
+	last, _ := time.Parse(time.RFC3339, requests[len(requests)-1].Timestamp)
+	duration := last.Sub(first)
+	if duration.Minutes() >= 60 {
+		return nil
+	}
+	e.requestsReported[entry.UserID] = true
+
+	return &AnomalyResult{
+		Type:        "suspicious_api_access",
+		Description: fmt.Sprintf("User %s made %d API requests in %.1f minutes", entry.UserID, len(requests), duration.Minutes()),
+		Count:       len(requests),
+		TimeWindow:  fmt.Sprintf("%.1f minutes", duration.Minutes()),
+		FirstSeen:   first,
+		LastSeen:    last,
+		Entries:     limitEntries(requests, 10),
+	}
+}
+
+func (e *streamAnomalyEngine) feedIP(entry LogEntry, t time.Time) *AnomalyResult {
+	if e.ipUsers[entry.IP] == nil {
+		e.ipUsers[entry.IP] = make(map[string]LogEntry)
+	}
+	for user, last := range e.ipUsers[entry.IP] {
+		if lt, err := time.Parse(time.RFC3339, last.Timestamp); err == nil && t.Sub(lt) > ipAnomalyWindow {
+			delete(e.ipUsers[entry.IP], user)
+		}
+	}
+	e.ipUsers[entry.IP][entry.UserID] = entry
+
+	users := e.ipUsers[entry.IP]
+	if len(users) <= 5 || e.ipReported[entry.IP] {
+		return nil
+	}
+	e.ipReported[entry.IP] = true
+
+	var userList []string
+	var recent []LogEntry
+	for user, last := range users {
+		userList = append(userList, user)
+		recent = append(recent, last)
+	}
+	sort.Strings(userList)
+	sort.Slice(recent, func(i, j int) bool { return recent[i].Timestamp < recent[j].Timestamp })
+
+	first, _ := time.Parse(time.RFC3339, recent[0].Timestamp)
+	last, _ := time.Parse(time.RFC3339, recent[len(recent)-1].Timestamp)
+
+	return &AnomalyResult{
+		Type:        "ip_multiple_users",
+		Description: fmt.Sprintf("IP %s accessed by %d different users: %s", entry.IP, len(users), strings.Join(userList, ", ")),
+		Count:       len(recent),
+		TimeWindow:  fmt.Sprintf("%.1f hours", last.Sub(first).Hours()),
+		FirstSeen:   first,
+		LastSeen:    last,
+		Entries:     limitEntries(recent, 10),
+	}
+}
+
+// feedImpossibleTravel compares entry against the user's last geo-enriched
+// entry and reports an impossible_travel anomaly if the implied speed
+// between the two IPs exceeds impossibleTravelSpeedKmh. It requires
+// --geoip-db to have populated entry.Extra["geo_lat"]/["geo_lon"].
+func (e *streamAnomalyEngine) feedImpossibleTravel(entry LogEntry, t time.Time) *AnomalyResult {
+	prev, ok := e.lastGeo[entry.UserID]
+	e.lastGeo[entry.UserID] = entry
+	if !ok || prev.IP == entry.IP {
+		return nil
+	}
+
+	prevTime, err := time.Parse(time.RFC3339, prev.Timestamp)
+	if err != nil {
+		return nil
+	}
+	elapsed := t.Sub(prevTime)
+	if elapsed <= 0 {
+		return nil
+	}
+
+	prevLat, ok1 := prev.Extra["geo_lat"].(float64)
+	prevLon, ok2 := prev.Extra["geo_lon"].(float64)
+	currLat, ok3 := entry.Extra["geo_lat"].(float64)
+	currLon, ok4 := entry.Extra["geo_lon"].(float64)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return nil
+	}
+
+	distanceKm := enrich.HaversineKM(prevLat, prevLon, currLat, currLon)
+	speedKmh := distanceKm / elapsed.Hours()
+	if speedKmh <= impossibleTravelSpeedKmh {
+		return nil
+	}
+
+	return &AnomalyResult{
+		Type:        "impossible_travel",
+		Description: fmt.Sprintf("User %s moved %.0f km from %s to %s in %s (%.0f km/h)", entry.UserID, distanceKm, prev.IP, entry.IP, elapsed, speedKmh),
+		Count:       2,
+		TimeWindow:  elapsed.String(),
+		FirstSeen:   prevTime,
+		LastSeen:    t,
+		Entries:     []LogEntry{prev, entry},
+		Extra: map[string]interface{}{
+			"from_ip":     prev.IP,
+			"to_ip":       entry.IP,
+			"distance_km": distanceKm,
+			"speed_kmh":   speedKmh,
+		},
+	}
+}
+
+// feedKnownMaliciousIP reports a known_malicious_ip anomaly the first time
+// an IP that matched --threat-feed is seen, then keeps accumulating its
+// entries silently so a single source can't spam repeated alerts.
+func (e *streamAnomalyEngine) feedKnownMaliciousIP(entry LogEntry, t time.Time) *AnomalyResult {
+	e.maliciousEntries[entry.IP] = append(e.maliciousEntries[entry.IP], entry)
+	if feed, ok := entry.Extra["threat_feed"].(string); ok {
+		e.maliciousFeed[entry.IP] = feed
+	}
+
+	if e.maliciousReported[entry.IP] {
+		return nil
+	}
+	e.maliciousReported[entry.IP] = true
+
+	entries := e.maliciousEntries[entry.IP]
+	return &AnomalyResult{
+		Type:        "known_malicious_ip",
+		Description: fmt.Sprintf("IP %s (threat feed %q) was active", entry.IP, e.maliciousFeed[entry.IP]),
+		Count:       len(entries),
+		TimeWindow:  "n/a",
+		FirstSeen:   t,
+		LastSeen:    t,
+		Entries:     limitEntries(entries, 10),
+		Extra: map[string]interface{}{
+			"threat_feed": e.maliciousFeed[entry.IP],
+		},
+	}
+}
+
+// trimOlderThan drops the leading entries of a chronologically-ordered
+// slice that fall outside window of now, bounding it to roughly one
+// window's worth of activity.
+func trimOlderThan(entries []LogEntry, now time.Time, window time.Duration) []LogEntry {
+	cut := 0
+	for i, entry := range entries {
+		ts, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil || now.Sub(ts) <= window {
+			break
+		}
+		cut = i + 1
+	}
+	if cut == 0 {
+		return entries
+	}
+	return append([]LogEntry(nil), entries[cut:]...)
+}
+
+// limitEntries caps the Entries payload on an AnomalyResult at n, matching
+// the batch detectors' convention of including a representative sample
+// rather than every contributing entry.
+func limitEntries(entries []LogEntry, n int) []LogEntry {
+	if len(entries) <= n {
+		return append([]LogEntry(nil), entries...)
+	}
+	return append([]LogEntry(nil), entries[:n]...)
+}
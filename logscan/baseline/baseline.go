@@ -0,0 +1,110 @@
+// Package baseline implements the exponentially weighted moving average
+// (EWMA) baseline behind logscan's adaptive anomaly detector: one Tracker
+// per detection key learns the typical per-bucket event rate and flags
+// buckets that deviate from it by more than k standard deviations, per
+// μ_t = α·x_t + (1-α)·μ_{t-1} and σ²_t = α·(x_t-μ_{t-1})² + (1-α)·σ²_{t-1}.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// minStddevFloor keeps a perfectly flat baseline (Var == 0, e.g. a key
+// that has seen the same count every bucket so far) from either dividing
+// by zero or permanently refusing to alert; without it a single deviation
+// from an until-now-constant rate could never be flagged as anomalous.
+const minStddevFloor = 0.5
+
+// Tracker holds one key's EWMA mean/variance of per-bucket counts.
+type Tracker struct {
+	Mean    float64 `json:"mean"`
+	Var     float64 `json:"var"`
+	Samples int     `json:"samples"`
+}
+
+// Observe feeds bucket count x through the EWMA update and reports
+// whether x is anomalous (more than k standard deviations above the mean
+// the baseline had going into this bucket), along with its z-score.
+// Buckets before warmup samples have been observed never report
+// anomalous, since the baseline isn't trustworthy yet.
+func (t *Tracker) Observe(x, alpha, k float64, warmup int) (anomalous bool, z float64) {
+	if t.Samples == 0 {
+		t.Mean = x
+		t.Samples = 1
+		return false, 0
+	}
+
+	stddev := math.Max(math.Sqrt(t.Var), minStddevFloor)
+	if t.Samples >= warmup {
+		z = (x - t.Mean) / stddev
+		anomalous = z > k
+	}
+
+	deviation := x - t.Mean
+	t.Mean = alpha*x + (1-alpha)*t.Mean
+	t.Var = alpha*deviation*deviation + (1-alpha)*t.Var
+	t.Samples++
+
+	return anomalous, z
+}
+
+// Store is a persisted set of Trackers keyed by detection key (e.g.
+// "<rule>|<group value>"), so a `logscan baseline` training run can warm
+// up the detector before a later `--rules ... --anomalies` run starts
+// cold.
+type Store struct {
+	Trackers map[string]*Tracker `json:"trackers"`
+}
+
+// NewStore returns an empty Store.
+func NewStore() *Store {
+	return &Store{Trackers: make(map[string]*Tracker)}
+}
+
+// LoadStore reads a Store from path, returning an empty Store if path
+// doesn't exist yet (a run with --baseline-file set before any `logscan
+// baseline` has trained one).
+func LoadStore(path string) (*Store, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewStore(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %v", err)
+	}
+
+	var s Store
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file: %v", err)
+	}
+	if s.Trackers == nil {
+		s.Trackers = make(map[string]*Tracker)
+	}
+	return &s, nil
+}
+
+// Save persists the Store to path as indented JSON.
+func (s *Store) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline file: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %v", err)
+	}
+	return nil
+}
+
+// Tracker returns the Tracker for key, creating one if it doesn't exist
+// yet.
+func (s *Store) Tracker(key string) *Tracker {
+	t, ok := s.Trackers[key]
+	if !ok {
+		t = &Tracker{}
+		s.Trackers[key] = t
+	}
+	return t
+}
@@ -0,0 +1,58 @@
+package baseline
+
+import "testing"
+
+func TestTrackerObserveWarmup(t *testing.T) {
+	tr := &Tracker{}
+
+	for i := 0; i < 10; i++ {
+		if anomalous, _ := tr.Observe(5, 0.1, 3, 10); anomalous {
+			t.Errorf("sample %d: expected no anomaly during warmup", i)
+		}
+	}
+}
+
+func TestTrackerObserveFlagsSpike(t *testing.T) {
+	tr := &Tracker{}
+
+	for i := 0; i < 20; i++ {
+		tr.Observe(5, 0.1, 3, 10)
+	}
+
+	anomalous, z := tr.Observe(500, 0.1, 3, 10)
+	if !anomalous {
+		t.Errorf("expected a 500-count bucket against a baseline of ~5 to be anomalous")
+	}
+	if z <= 3 {
+		t.Errorf("expected z-score > k (3), got %.2f", z)
+	}
+}
+
+func TestStoreSaveAndLoad(t *testing.T) {
+	path := t.TempDir() + "/baseline.json"
+
+	store := NewStore()
+	store.Tracker("rule|alice").Observe(5, 0.1, 3, 10)
+
+	if err := store.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore failed: %v", err)
+	}
+	if loaded.Tracker("rule|alice").Samples != 1 {
+		t.Errorf("Expected 1 sample after reload, got %d", loaded.Tracker("rule|alice").Samples)
+	}
+}
+
+func TestLoadStoreMissingFileReturnsEmpty(t *testing.T) {
+	store, err := LoadStore(t.TempDir() + "/missing.json")
+	if err != nil {
+		t.Fatalf("Expected no error for missing baseline file, got %v", err)
+	}
+	if len(store.Trackers) != 0 {
+		t.Errorf("Expected empty store, got %d trackers", len(store.Trackers))
+	}
+}
@@ -0,0 +1,105 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// minMADFloor mirrors minStddevFloor: it keeps a perfectly flat baseline
+// (MAD == 0, e.g. a key that has seen the same count every bucket so far)
+// from either dividing by zero or permanently refusing to alert.
+const minMADFloor = 0.5
+
+// MADTracker holds one key's EWMA of per-bucket counts and a companion
+// EWMA of the absolute deviation from that mean, a robust (outlier-resistant)
+// alternative to Tracker's variance-based standard deviation: per
+// EWMA_t = α·x_t + (1-α)·EWMA_{t-1}, MAD_t = α·|x_t-EWMA_{t-1}| + (1-α)·MAD_{t-1}.
+type MADTracker struct {
+	EWMA    float64 `json:"ewma"`
+	MAD     float64 `json:"mad"`
+	Samples int     `json:"samples"`
+}
+
+// Observe feeds bucket count x through the EWMA/MAD update and reports
+// whether x is anomalous (more than k MADs above the mean the baseline had
+// going into this bucket), along with how many MADs above that it was.
+// Buckets before warmup samples have been observed never report anomalous,
+// since the baseline isn't trustworthy yet.
+func (t *MADTracker) Observe(x, alpha, k float64, warmup int) (anomalous bool, deviation float64) {
+	if t.Samples == 0 {
+		t.EWMA = x
+		t.Samples = 1
+		return false, 0
+	}
+
+	mad := math.Max(t.MAD, minMADFloor)
+	if t.Samples >= warmup {
+		deviation = (x - t.EWMA) / mad
+		anomalous = x > t.EWMA+k*mad
+	}
+
+	absDeviation := math.Abs(x - t.EWMA)
+	t.EWMA = alpha*x + (1-alpha)*t.EWMA
+	t.MAD = alpha*absDeviation + (1-alpha)*t.MAD
+	t.Samples++
+
+	return anomalous, deviation
+}
+
+// MADStore is a persisted set of MADTrackers keyed by detection key (e.g.
+// "user_rate|alice"), so a long-running statistical detector doesn't lose
+// its learned baselines across restarts.
+type MADStore struct {
+	Trackers map[string]*MADTracker `json:"trackers"`
+}
+
+// NewMADStore returns an empty MADStore.
+func NewMADStore() *MADStore {
+	return &MADStore{Trackers: make(map[string]*MADTracker)}
+}
+
+// LoadMADStore reads a MADStore from path, returning an empty MADStore if
+// path doesn't exist yet (the first run with a given --stat-baseline file).
+func LoadMADStore(path string) (*MADStore, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewMADStore(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read statistical baseline file: %v", err)
+	}
+
+	var s MADStore
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse statistical baseline file: %v", err)
+	}
+	if s.Trackers == nil {
+		s.Trackers = make(map[string]*MADTracker)
+	}
+	return &s, nil
+}
+
+// Save persists the MADStore to path as indented JSON.
+func (s *MADStore) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode statistical baseline file: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write statistical baseline file: %v", err)
+	}
+	return nil
+}
+
+// Tracker returns the MADTracker for key, creating one if it doesn't exist
+// yet.
+func (s *MADStore) Tracker(key string) *MADTracker {
+	t, ok := s.Trackers[key]
+	if !ok {
+		t = &MADTracker{}
+		s.Trackers[key] = t
+	}
+	return t
+}
@@ -0,0 +1,69 @@
+package baseline
+
+import "testing"
+
+func TestMADTrackerObserveWarmup(t *testing.T) {
+	tr := &MADTracker{}
+
+	for i := 0; i < 10; i++ {
+		if anomalous, _ := tr.Observe(5, 0.05, 4, 10); anomalous {
+			t.Errorf("sample %d: expected no anomaly during warmup", i)
+		}
+	}
+}
+
+func TestMADTrackerObserveIgnoresOrdinaryVariation(t *testing.T) {
+	tr := &MADTracker{}
+
+	samples := []float64{5, 4, 6, 5, 7, 4, 5, 6, 5, 4, 6, 5}
+	for i, x := range samples {
+		if anomalous, _ := tr.Observe(x, 0.05, 4, 10); anomalous {
+			t.Errorf("sample %d (%.0f): expected ordinary variation around a baseline of ~5 not to be flagged", i, x)
+		}
+	}
+}
+
+func TestMADTrackerObserveFlagsSpike(t *testing.T) {
+	tr := &MADTracker{}
+
+	for i := 0; i < 20; i++ {
+		tr.Observe(5, 0.05, 4, 10)
+	}
+
+	anomalous, deviation := tr.Observe(50, 0.05, 4, 10)
+	if !anomalous {
+		t.Errorf("expected a 50-count bucket against a baseline of ~5 to be anomalous")
+	}
+	if deviation <= 4 {
+		t.Errorf("expected deviation > k (4) MADs, got %.2f", deviation)
+	}
+}
+
+func TestMADStoreSaveAndLoad(t *testing.T) {
+	path := t.TempDir() + "/stat-baseline.json"
+
+	store := NewMADStore()
+	store.Tracker("user_rate|alice").Observe(5, 0.05, 4, 10)
+
+	if err := store.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadMADStore(path)
+	if err != nil {
+		t.Fatalf("LoadMADStore failed: %v", err)
+	}
+	if loaded.Tracker("user_rate|alice").Samples != 1 {
+		t.Errorf("Expected 1 sample after reload, got %d", loaded.Tracker("user_rate|alice").Samples)
+	}
+}
+
+func TestLoadMADStoreMissingFileReturnsEmpty(t *testing.T) {
+	store, err := LoadMADStore(t.TempDir() + "/missing.json")
+	if err != nil {
+		t.Fatalf("Expected no error for missing statistical baseline file, got %v", err)
+	}
+	if len(store.Trackers) != 0 {
+		t.Errorf("Expected empty store, got %d trackers", len(store.Trackers))
+	}
+}
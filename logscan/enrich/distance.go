@@ -0,0 +1,21 @@
+package enrich
+
+import "math"
+
+const earthRadiusKm = 6371.0
+
+// HaversineKM returns the great-circle distance in kilometers between two
+// lat/lon points, used by the impossible_travel detector to turn a pair
+// of GeoIP lookups into a travel speed.
+func HaversineKM(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+
+	return earthRadiusKm * c
+}
@@ -0,0 +1,66 @@
+package enrich
+
+import "github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+
+// Enricher attaches GeoIP and threat-intel context to log entries ahead
+// of detection. Either input may be nil/empty independently, so --geoip-db
+// and --threat-feed can be used alone or together.
+type Enricher struct {
+	geo   *GeoIPReader
+	feeds []*ThreatFeed
+}
+
+// NewEnricher builds an Enricher from an optional GeoIP reader (nil
+// disables geo enrichment) and zero or more threat feeds.
+func NewEnricher(geo *GeoIPReader, feeds []*ThreatFeed) *Enricher {
+	return &Enricher{geo: geo, feeds: feeds}
+}
+
+// Enrich populates entry.Extra with "geo_country", "geo_city", "geo_lat",
+// "geo_lon" (and, if the database carries them, "geo_asn"/"geo_as_org")
+// when a GeoIP reader is configured and resolves entry.IP, and with
+// "threat_match"/"threat_feed" when entry.IP hits a loaded feed. It's a
+// no-op if entry.IP is empty.
+func (e *Enricher) Enrich(entry *source.LogEntry) {
+	if entry.IP == "" {
+		return
+	}
+
+	if e.geo != nil {
+		if info, err := e.geo.Lookup(entry.IP); err == nil {
+			ensureExtra(entry)
+			entry.Extra["geo_country"] = info.Country
+			entry.Extra["geo_city"] = info.City
+			entry.Extra["geo_lat"] = info.Lat
+			entry.Extra["geo_lon"] = info.Lon
+			if info.ASN != 0 {
+				entry.Extra["geo_asn"] = info.ASN
+				entry.Extra["geo_as_org"] = info.ASOrg
+			}
+		}
+	}
+
+	for _, feed := range e.feeds {
+		if feed.Contains(entry.IP) {
+			ensureExtra(entry)
+			entry.Extra["threat_match"] = true
+			entry.Extra["threat_feed"] = feed.Name
+			break
+		}
+	}
+}
+
+func ensureExtra(entry *source.LogEntry) {
+	if entry.Extra == nil {
+		entry.Extra = make(map[string]interface{})
+	}
+}
+
+// Close releases the GeoIP reader, if one is configured. Threat feeds
+// hold no open resources between refreshes.
+func (e *Enricher) Close() error {
+	if e.geo == nil {
+		return nil
+	}
+	return e.geo.Close()
+}
@@ -0,0 +1,136 @@
+package enrich
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+func writeTempFeed(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "feed.txt")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp feed: %v", err)
+	}
+	return path
+}
+
+func TestThreatFeedContains(t *testing.T) {
+	path := writeTempFeed(t, "# known-bad\n203.0.113.9\n198.51.100.0/24\n\n")
+
+	feed, err := NewThreatFeed("test-feed", path, 0)
+	if err != nil {
+		t.Fatalf("NewThreatFeed failed: %v", err)
+	}
+
+	cases := map[string]bool{
+		"203.0.113.9":   true,
+		"203.0.113.10":  false,
+		"198.51.100.42": true,
+		"192.0.2.1":     false,
+	}
+	for ip, want := range cases {
+		if got := feed.Contains(ip); got != want {
+			t.Errorf("Contains(%q) = %v, want %v", ip, got, want)
+		}
+	}
+}
+
+func TestThreatFeedRefreshesAfterTTL(t *testing.T) {
+	path := writeTempFeed(t, "203.0.113.9\n")
+
+	feed, err := NewThreatFeed("test-feed", path, time.Millisecond)
+	if err != nil {
+		t.Fatalf("NewThreatFeed failed: %v", err)
+	}
+	if !feed.Contains("203.0.113.9") {
+		t.Fatal("expected initial load to match 203.0.113.9")
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if err := os.WriteFile(path, []byte("198.51.100.1\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite feed: %v", err)
+	}
+
+	if feed.Contains("203.0.113.9") {
+		t.Error("expected the stale entry to be gone after a TTL-triggered refresh")
+	}
+	if !feed.Contains("198.51.100.1") {
+		t.Error("expected the new entry to be present after a TTL-triggered refresh")
+	}
+}
+
+func TestHaversineKMKnownDistance(t *testing.T) {
+	// London to Paris is roughly 344 km.
+	got := HaversineKM(51.5074, -0.1278, 48.8566, 2.3522)
+	if got < 330 || got > 360 {
+		t.Errorf("HaversineKM(London, Paris) = %.1f km, want ~344 km", got)
+	}
+
+	if got := HaversineKM(40.7128, -74.0060, 40.7128, -74.0060); got != 0 {
+		t.Errorf("HaversineKM of a point against itself = %.4f, want 0", got)
+	}
+}
+
+func TestOpenGeoIPRejectsNonMMDBFile(t *testing.T) {
+	path := writeTempFeed(t, "not an mmdb file\n")
+
+	if _, err := OpenGeoIP(path); err == nil {
+		t.Fatal("expected OpenGeoIP to reject a file that isn't a valid mmdb")
+	}
+}
+
+// TestGeoIPReaderLookup exercises Lookup against a real GeoLite2-City mmdb.
+// This repo doesn't bundle MaxMind's (non-redistributable) test fixture, so
+// this is skipped rather than faked; run it locally against
+// https://github.com/maxmind/MaxMind-DB/blob/main/test-data/GeoLite2-City-Test.mmdb
+// by setting GEOIP_TEST_MMDB to its path.
+func TestGeoIPReaderLookup(t *testing.T) {
+	path := os.Getenv("GEOIP_TEST_MMDB")
+	if path == "" {
+		t.Skip("GEOIP_TEST_MMDB not set; no fixture mmdb is bundled with this repo")
+	}
+
+	reader, err := OpenGeoIP(path)
+	if err != nil {
+		t.Fatalf("OpenGeoIP(%q) failed: %v", path, err)
+	}
+	defer reader.Close()
+
+	info, err := reader.Lookup("2.125.160.216")
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if info.Country == "" {
+		t.Error("expected a non-empty Country")
+	}
+}
+
+func TestEnricherAttachesThreatMatch(t *testing.T) {
+	path := writeTempFeed(t, "203.0.113.9\n")
+	feed, err := NewThreatFeed("test-feed", path, 0)
+	if err != nil {
+		t.Fatalf("NewThreatFeed failed: %v", err)
+	}
+
+	enricher := NewEnricher(nil, []*ThreatFeed{feed})
+
+	entry := source.LogEntry{IP: "203.0.113.9"}
+	enricher.Enrich(&entry)
+
+	if entry.Extra["threat_match"] != true {
+		t.Errorf("expected threat_match to be true, got %v", entry.Extra["threat_match"])
+	}
+	if entry.Extra["threat_feed"] != "test-feed" {
+		t.Errorf("expected threat_feed to be %q, got %v", "test-feed", entry.Extra["threat_feed"])
+	}
+
+	clean := source.LogEntry{IP: "192.0.2.1"}
+	enricher.Enrich(&clean)
+	if clean.Extra != nil {
+		t.Errorf("expected no Extra to be attached for a clean IP, got %v", clean.Extra)
+	}
+}
@@ -0,0 +1,73 @@
+// Package enrich adds GeoIP and threat-intel context to a LogEntry's Extra
+// map ahead of detection, so detectors and downstream --sink/--rules
+// consumers can reason about where an IP actually resolves to and whether
+// it's on a watchlist instead of just seeing the raw address string.
+package enrich
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// GeoInfo is what a successful GeoIP lookup contributes to a LogEntry's
+// Extra map.
+type GeoInfo struct {
+	Country string
+	City    string
+	Lat     float64
+	Lon     float64
+	ASN     uint
+	ASOrg   string
+}
+
+// GeoIPReader looks up country/city/ASN from a MaxMind mmdb. A free
+// GeoLite2-City database only carries country/city/lat/lon; ASN/ASOrg are
+// populated only when --geoip-db points at a database edition that also
+// carries autonomous-system traits (e.g. a commercial GeoIP2-City).
+type GeoIPReader struct {
+	db *geoip2.Reader
+}
+
+// OpenGeoIP opens the mmdb at path.
+func OpenGeoIP(path string) (*GeoIPReader, error) {
+	db, err := geoip2.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP database %q: %v", path, err)
+	}
+	return &GeoIPReader{db: db}, nil
+}
+
+// Lookup resolves ipStr to a GeoInfo, or an error if ipStr doesn't parse
+// or isn't present in the database.
+func (g *GeoIPReader) Lookup(ipStr string) (*GeoInfo, error) {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address %q", ipStr)
+	}
+
+	record, err := g.db.City(ip)
+	if err != nil {
+		return nil, fmt.Errorf("GeoIP lookup for %s failed: %v", ipStr, err)
+	}
+
+	info := &GeoInfo{
+		Country: record.Country.IsoCode,
+		Lat:     record.Location.Latitude,
+		Lon:     record.Location.Longitude,
+	}
+	if name, ok := record.City.Names["en"]; ok {
+		info.City = name
+	}
+	if record.Traits.AutonomousSystemNumber != 0 {
+		info.ASN = record.Traits.AutonomousSystemNumber
+		info.ASOrg = record.Traits.AutonomousSystemOrganization
+	}
+	return info, nil
+}
+
+// Close releases the underlying mmdb file.
+func (g *GeoIPReader) Close() error {
+	return g.db.Close()
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/enrich"
+)
+
+// buildEnricher wires up the --geoip-db and --threat-feed flags into an
+// enrich.Enricher, or returns a nil Enricher if neither was set so callers
+// can skip enrichment entirely.
+func buildEnricher() (*enrich.Enricher, error) {
+	if geoipDBPath == "" && len(threatFeedSpecs) == 0 {
+		return nil, nil
+	}
+
+	var geo *enrich.GeoIPReader
+	if geoipDBPath != "" {
+		var err error
+		geo, err = enrich.OpenGeoIP(geoipDBPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open --geoip-db %q: %v", geoipDBPath, err)
+		}
+	}
+
+	var feeds []*enrich.ThreatFeed
+	for _, spec := range threatFeedSpecs {
+		name := strings.TrimSuffix(filepath.Base(spec), filepath.Ext(spec))
+		feed, err := enrich.NewThreatFeed(name, spec, threatFeedTTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load --threat-feed %q: %v", spec, err)
+		}
+		feeds = append(feeds, feed)
+	}
+
+	return enrich.NewEnricher(geo, feeds), nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestBuildEnricherNoopWithoutFlags(t *testing.T) {
+	prevGeoip, prevFeeds := geoipDBPath, threatFeedSpecs
+	geoipDBPath, threatFeedSpecs = "", nil
+	t.Cleanup(func() { geoipDBPath, threatFeedSpecs = prevGeoip, prevFeeds })
+
+	enricher, err := buildEnricher()
+	if err != nil {
+		t.Fatalf("buildEnricher: %v", err)
+	}
+	if enricher != nil {
+		t.Errorf("expected a nil Enricher when --geoip-db/--threat-feed are both unset, got %+v", enricher)
+	}
+}
+
+func TestBuildEnricherRejectsMissingGeoIPDB(t *testing.T) {
+	prevGeoip, prevFeeds := geoipDBPath, threatFeedSpecs
+	geoipDBPath, threatFeedSpecs = "/nonexistent/geoip.mmdb", nil
+	t.Cleanup(func() { geoipDBPath, threatFeedSpecs = prevGeoip, prevFeeds })
+
+	if _, err := buildEnricher(); err == nil {
+		t.Fatal("expected an error when --geoip-db points at a nonexistent file")
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/enrich"
+)
+
+// impossibleTravelSpeedKmh is the travel speed between two consecutive
+// geo-resolved IPs for the same user above which the move is physically
+// implausible (commercial air travel tops out well below this).
+const impossibleTravelSpeedKmh = 800.0
+
+// detectImpossibleTravel flags a user whose consecutive requests resolve to
+// two different IPs that are geographically farther apart than they could
+// plausibly have traveled in the elapsed time. It requires --geoip-db to
+// have populated entry.Extra["geo_lat"]/["geo_lon"].
+func detectImpossibleTravel(entries []LogEntry) []AnomalyResult {
+	var anomalies []AnomalyResult
+
+	byUser := make(map[string][]LogEntry)
+	for _, entry := range entries {
+		if entry.IP == "" || entry.UserID == "" {
+			continue
+		}
+		if _, ok := entry.Extra["geo_lat"]; !ok {
+			continue
+		}
+		byUser[entry.UserID] = append(byUser[entry.UserID], entry)
+	}
+
+	for user, userEntries := range byUser {
+		sort.Slice(userEntries, func(i, j int) bool {
+			return userEntries[i].Timestamp < userEntries[j].Timestamp
+		})
+
+		for i := 1; i < len(userEntries); i++ {
+			prev, curr := userEntries[i-1], userEntries[i]
+			if prev.IP == curr.IP {
+				continue
+			}
+
+			prevTime, err1 := time.Parse(time.RFC3339, prev.Timestamp)
+			currTime, err2 := time.Parse(time.RFC3339, curr.Timestamp)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+
+			elapsed := currTime.Sub(prevTime)
+			if elapsed <= 0 {
+				continue
+			}
+
+			prevLat, ok1 := prev.Extra["geo_lat"].(float64)
+			prevLon, ok2 := prev.Extra["geo_lon"].(float64)
+			currLat, ok3 := curr.Extra["geo_lat"].(float64)
+			currLon, ok4 := curr.Extra["geo_lon"].(float64)
+			if !ok1 || !ok2 || !ok3 || !ok4 {
+				continue
+			}
+
+			distanceKm := enrich.HaversineKM(prevLat, prevLon, currLat, currLon)
+			speedKmh := distanceKm / elapsed.Hours()
+			if speedKmh <= impossibleTravelSpeedKmh {
+				continue
+			}
+
+			anomalies = append(anomalies, AnomalyResult{
+				Type:        "impossible_travel",
+				Description: fmt.Sprintf("User %s moved %.0f km from %s to %s in %s (%.0f km/h)", user, distanceKm, prev.IP, curr.IP, elapsed, speedKmh),
+				Count:       2,
+				TimeWindow:  elapsed.String(),
+				FirstSeen:   prevTime,
+				LastSeen:    currTime,
+				Entries:     []LogEntry{prev, curr},
+				Extra: map[string]interface{}{
+					"from_ip":     prev.IP,
+					"to_ip":       curr.IP,
+					"distance_km": distanceKm,
+					"speed_kmh":   speedKmh,
+				},
+			})
+		}
+	}
+
+	return anomalies
+}
+
+// detectKnownMaliciousIP flags any IP that --threat-feed matched, one
+// anomaly per IP summarizing all the activity seen from it.
+func detectKnownMaliciousIP(entries []LogEntry) []AnomalyResult {
+	var anomalies []AnomalyResult
+
+	byIP := make(map[string][]LogEntry)
+	feedByIP := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IP == "" || entry.Extra["threat_match"] != true {
+			continue
+		}
+		byIP[entry.IP] = append(byIP[entry.IP], entry)
+		if feed, ok := entry.Extra["threat_feed"].(string); ok {
+			feedByIP[entry.IP] = feed
+		}
+	}
+
+	for ip, ipEntries := range byIP {
+		sort.Slice(ipEntries, func(i, j int) bool {
+			return ipEntries[i].Timestamp < ipEntries[j].Timestamp
+		})
+
+		firstTime, _ := time.Parse(time.RFC3339, ipEntries[0].Timestamp)
+		lastTime, _ := time.Parse(time.RFC3339, ipEntries[len(ipEntries)-1].Timestamp)
+
+		limit := len(ipEntries)
+		if limit > 10 {
+			limit = 10
+		}
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "known_malicious_ip",
+			Description: fmt.Sprintf("IP %s (threat feed %q) was active %d times", ip, feedByIP[ip], len(ipEntries)),
+			Count:       len(ipEntries),
+			TimeWindow:  fmt.Sprintf("%.1f hours", lastTime.Sub(firstTime).Hours()),
+			FirstSeen:   firstTime,
+			LastSeen:    lastTime,
+			Entries:     ipEntries[:limit],
+			Extra: map[string]interface{}{
+				"threat_feed": feedByIP[ip],
+			},
+		})
+	}
+
+	return anomalies
+}
+
+// detectHighRiskASN flags any IP whose GeoIP-resolved autonomous system is
+// on the --high-risk-asn list (a hosting/VPN provider known to front
+// abusive traffic), one anomaly per ASN summarizing all the activity seen
+// from it. It requires --geoip-db to have populated
+// entry.Extra["geo_asn"] from an edition of the mmdb that carries ASN
+// traits.
+func detectHighRiskASN(entries []LogEntry) []AnomalyResult {
+	if len(highRiskASNs) == 0 {
+		return nil
+	}
+
+	watched := make(map[uint]bool, len(highRiskASNs))
+	for _, spec := range highRiskASNs {
+		asn, err := strconv.ParseUint(spec, 10, 32)
+		if err != nil {
+			continue
+		}
+		watched[uint(asn)] = true
+	}
+
+	byASN := make(map[uint][]LogEntry)
+	orgByASN := make(map[uint]string)
+	for _, entry := range entries {
+		asn, ok := entry.Extra["geo_asn"].(uint)
+		if !ok || !watched[asn] {
+			continue
+		}
+		byASN[asn] = append(byASN[asn], entry)
+		if org, ok := entry.Extra["geo_as_org"].(string); ok {
+			orgByASN[asn] = org
+		}
+	}
+
+	var anomalies []AnomalyResult
+	for asn, asnEntries := range byASN {
+		sort.Slice(asnEntries, func(i, j int) bool {
+			return asnEntries[i].Timestamp < asnEntries[j].Timestamp
+		})
+
+		firstTime, _ := time.Parse(time.RFC3339, asnEntries[0].Timestamp)
+		lastTime, _ := time.Parse(time.RFC3339, asnEntries[len(asnEntries)-1].Timestamp)
+
+		limit := len(asnEntries)
+		if limit > 10 {
+			limit = 10
+		}
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "high_risk_asn",
+			Description: fmt.Sprintf("ASN %d (%q) was active %d times", asn, orgByASN[asn], len(asnEntries)),
+			Count:       len(asnEntries),
+			TimeWindow:  fmt.Sprintf("%.1f hours", lastTime.Sub(firstTime).Hours()),
+			FirstSeen:   firstTime,
+			LastSeen:    lastTime,
+			Entries:     asnEntries[:limit],
+			Extra: map[string]interface{}{
+				"asn":    asn,
+				"as_org": orgByASN[asn],
+			},
+		})
+	}
+
+	return anomalies
+}
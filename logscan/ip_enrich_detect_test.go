@@ -0,0 +1,71 @@
+package main
+
+import "testing"
+
+// withHighRiskASNs sets the --high-risk-asn flag var for the duration of a
+// test and restores it afterward, since detectHighRiskASN reads the
+// package-level flag var directly (the same pattern runScenarioDetection's
+// tests use for scenariosPath).
+func withHighRiskASNs(t *testing.T, asns []string) {
+	t.Helper()
+	prev := highRiskASNs
+	highRiskASNs = asns
+	t.Cleanup(func() { highRiskASNs = prev })
+}
+
+func TestDetectHighRiskASNFlagsWatchedASN(t *testing.T) {
+	withHighRiskASNs(t, []string{"14061"})
+
+	entries := []LogEntry{
+		{
+			Timestamp: "2024-01-01T00:00:00Z",
+			IP:        "203.0.113.5",
+			Extra:     map[string]interface{}{"geo_asn": uint(14061), "geo_as_org": "DigitalOcean, LLC"},
+		},
+		{
+			Timestamp: "2024-01-01T00:05:00Z",
+			IP:        "203.0.113.6",
+			Extra:     map[string]interface{}{"geo_asn": uint(14061), "geo_as_org": "DigitalOcean, LLC"},
+		},
+		{
+			Timestamp: "2024-01-01T00:10:00Z",
+			IP:        "198.51.100.7",
+			Extra:     map[string]interface{}{"geo_asn": uint(15169), "geo_as_org": "Google LLC"},
+		},
+	}
+
+	anomalies := detectHighRiskASN(entries)
+	if len(anomalies) != 1 {
+		t.Fatalf("expected exactly 1 anomaly for the watched ASN, got %d: %+v", len(anomalies), anomalies)
+	}
+	if anomalies[0].Count != 2 {
+		t.Errorf("expected Count 2 for ASN 14061's two entries, got %d", anomalies[0].Count)
+	}
+	if anomalies[0].Extra["asn"] != uint(14061) {
+		t.Errorf("expected Extra[\"asn\"] = 14061, got %v", anomalies[0].Extra["asn"])
+	}
+}
+
+func TestDetectHighRiskASNNoopWithoutFlag(t *testing.T) {
+	withHighRiskASNs(t, nil)
+
+	entries := []LogEntry{
+		{Timestamp: "2024-01-01T00:00:00Z", IP: "203.0.113.5", Extra: map[string]interface{}{"geo_asn": uint(14061)}},
+	}
+
+	if got := detectHighRiskASN(entries); got != nil {
+		t.Errorf("expected no anomalies with --high-risk-asn unset, got %+v", got)
+	}
+}
+
+func TestDetectHighRiskASNIgnoresUnenrichedEntries(t *testing.T) {
+	withHighRiskASNs(t, []string{"14061"})
+
+	entries := []LogEntry{
+		{Timestamp: "2024-01-01T00:00:00Z", IP: "203.0.113.5"},
+	}
+
+	if got := detectHighRiskASN(entries); got != nil {
+		t.Errorf("expected no anomalies for entries without --geoip-db enrichment, got %+v", got)
+	}
+}
@@ -3,37 +3,26 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/output"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
 	"github.com/spf13/cobra"
 )
 
-type LogEntry struct {
-	Timestamp string                 `json:"timestamp"`
-	Level     string                 `json:"level"`
-	Message   string                 `json:"message"`
-	UserID    string                 `json:"user_id"`
-	IP        string                 `json:"ip"`
-	Action    string                 `json:"action"`
-	Endpoint  string                 `json:"endpoint"`
-	Status    int                    `json:"status"`
-	Duration  float64                `json:"duration"`
-	Extra     map[string]interface{} `json:"extra"`
-}
+// LogEntry is an alias for source.LogEntry so the rest of this package
+// (filters, anomaly detectors, output formatting) doesn't need to know
+// that entries are decoded by a pluggable source.Source underneath.
+type LogEntry = source.LogEntry
 
-type AnomalyResult struct {
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	Count       int       `json:"count"`
-	TimeWindow  string    `json:"time_window"`
-	FirstSeen   time.Time `json:"first_seen"`
-	LastSeen    time.Time `json:"last_seen"`
-	Entries     []LogEntry `json:"entries"`
-}
+// AnomalyResult is an alias for output.Event so --sink destinations can
+// be fed the exact same values outputResults writes to --output.
+type AnomalyResult = output.Event
 
 var (
 	inputFile    string
@@ -45,6 +34,18 @@ var (
 	detectAnomalies bool
 	verbose      bool
 	format       string
+	formatIn     string
+	tailInput    bool
+	rulesFile      string
+	baselineFile   string
+	sigmaRulesPath string
+	statBaselineFile string
+	scenariosPath  string
+	sinkSpecs      []string
+	geoipDBPath    string
+	threatFeedSpecs []string
+	threatFeedTTL   time.Duration
+	highRiskASNs   []string
 )
 
 var rootCmd = &cobra.Command{
@@ -78,39 +79,148 @@ func init() {
 	rootCmd.Flags().BoolVar(&detectAnomalies, "anomalies", false, "Enable anomaly detection")
 	rootCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
 	rootCmd.Flags().StringVar(&format, "format", "json", "Output format: json, table, csv")
-	
+	rootCmd.Flags().StringVar(&formatIn, "format-in", "", "Input log format: jsonl, syslog, clf (default: auto-detect by extension/content); .gz and .zst are decompressed transparently")
+	rootCmd.Flags().BoolVar(&tailInput, "tail", false, "Keep following the input file and process appended lines continuously, like tail -f")
+	rootCmd.Flags().StringVar(&rulesFile, "rules", "", "YAML rules file of adaptive (EWMA) detection rules; without it, --anomalies uses the built-in fixed thresholds")
+	rootCmd.Flags().StringVar(&baselineFile, "baseline-file", "", "Baseline file (written by the 'baseline' subcommand) to warm-start --rules detection from")
+	rootCmd.Flags().StringVar(&sigmaRulesPath, "sigma-rules", "", "Sigma-style YAML rule file or directory to evaluate alongside --anomalies ('builtin' loads the rules in logscan/sigma/builtin)")
+	rootCmd.Flags().StringVar(&statBaselineFile, "stat-baseline", "", "JSON checkpoint file for detectStatisticalAnomalies's per-user/per-endpoint EWMA+MAD baselines, evaluated alongside --anomalies (created on first run, updated on every run after)")
+	rootCmd.Flags().StringVar(&scenariosPath, "scenarios", "", "CrowdSec-style YAML scenario file or directory to evaluate alongside --anomalies (see logscan/scenario)")
+	rootCmd.Flags().StringArrayVar(&sinkSpecs, "sink", nil, "Feed detected anomalies to a downstream destination (repeatable for fan-out): "+
+		"slog://stdout, es://host:9200/index, hec://token@host:8088, syslog+tcp://host:601, webhook://host/path, cef+udp://host:514, leef+tcp://host:514")
+	rootCmd.Flags().StringVar(&geoipDBPath, "geoip-db", "", "Path to a MaxMind GeoLite2/GeoIP2 mmdb used to enrich LogEntry.IP with country/city/ASN before detection")
+	rootCmd.Flags().StringArrayVar(&threatFeedSpecs, "threat-feed", nil, "Threat-intel feed of known-malicious IPs/CIDRs (repeatable): a local file path or an http(s):// URL, one entry per line")
+	rootCmd.Flags().DurationVar(&threatFeedTTL, "threat-feed-ttl", time.Hour, "How often to re-fetch each --threat-feed")
+	rootCmd.Flags().StringArrayVar(&highRiskASNs, "high-risk-asn", nil, "ASN of a known hosting/VPN provider to flag as detectHighRiskASN (repeatable); requires --geoip-db to have populated entry.Extra[\"geo_asn\"]")
+
 	rootCmd.MarkFlagRequired("input")
 }
 
 func runLogScan(cmd *cobra.Command, args []string) {
+	if tailInput {
+		runTailLogScan()
+		return
+	}
+
+	sink, err := buildSinks()
+	if err != nil {
+		log.Fatalf("Failed to set up --sink: %v", err)
+	}
+	if sink != nil {
+		defer sink.Close()
+	}
+
+	enricher, err := buildEnricher()
+	if err != nil {
+		log.Fatalf("Failed to set up GeoIP/threat-intel enrichment: %v", err)
+	}
+	if enricher != nil {
+		defer enricher.Close()
+	}
+
+	// The built-in fixed-threshold detectors (no --rules/--sigma/--stat-baseline/
+	// --scenarios, no enrichment to apply in place) can run as a pure
+	// streaming pipeline: decode, filter, and feed the fan-out detectors one
+	// entry at a time, without ever materializing the file, so multi-GB
+	// inputs process in bounded memory. --rules/--sigma/--stat-baseline/
+	// --scenarios/enrichment/plain listing still need the []LogEntry batch
+	// APIs they were built against, so they fall through to readLogFile below.
+	if detectAnomalies && rulesFile == "" && sigmaRulesPath == "" && statBaselineFile == "" && scenariosPath == "" && enricher == nil {
+		if verbose {
+			log.Printf("Streaming log file: %s", inputFile)
+		}
+		raw, err := streamLogEntries(inputFile)
+		if err != nil {
+			log.Fatalf("Failed to read log file: %v", err)
+		}
+
+		filtered := make(chan LogEntry, 256)
+		go func() {
+			defer close(filtered)
+			for entry := range raw {
+				if matchesFilters(entry) {
+					filtered <- entry
+				}
+			}
+		}()
+
+		anomalies := detectSecurityAnomaliesStreaming(filtered)
+		if verbose {
+			log.Printf("Detected %d anomalies", len(anomalies))
+		}
+		sendToSinks(sink, anomalies)
+
+		if err := outputResults(anomalies); err != nil {
+			log.Fatalf("Failed to output results: %v", err)
+		}
+		return
+	}
+
 	if verbose {
 		log.Printf("Reading log file: %s", inputFile)
 	}
-	
+
 	entries, err := readLogFile(inputFile)
 	if err != nil {
 		log.Fatalf("Failed to read log file: %v", err)
 	}
-	
+
 	if verbose {
 		log.Printf("Loaded %d log entries", len(entries))
 	}
-	
+
 	// Apply filters
 	filtered := applyFilters(entries)
-	
+
 	if verbose {
 		log.Printf("After filtering: %d entries", len(filtered))
 	}
-	
+
+	if enricher != nil {
+		for i := range filtered {
+			enricher.Enrich(&filtered[i])
+		}
+	}
+
 	var results interface{}
-	
+
 	if detectAnomalies {
-		anomalies := detectSecurityAnomalies(filtered)
+		var anomalies []AnomalyResult
+		var err error
+		if rulesFile != "" {
+			anomalies, err = runAdaptiveDetection(filtered)
+		} else {
+			anomalies = detectSecurityAnomalies(filtered)
+		}
+		if err != nil {
+			log.Fatalf("Adaptive detection failed: %v", err)
+		}
+		if sigmaRulesPath != "" {
+			sigmaAnomalies, err := runSigmaDetection(filtered)
+			if err != nil {
+				log.Fatalf("Sigma rule detection failed: %v", err)
+			}
+			anomalies = append(anomalies, sigmaAnomalies...)
+		}
+		if statBaselineFile != "" {
+			statAnomalies, err := runStatisticalDetection(filtered)
+			if err != nil {
+				log.Fatalf("Statistical detection failed: %v", err)
+			}
+			anomalies = append(anomalies, statAnomalies...)
+		}
+		if scenariosPath != "" {
+			scenarioAnomalies, err := runScenarioDetection(filtered)
+			if err != nil {
+				log.Fatalf("Scenario detection failed: %v", err)
+			}
+			anomalies = append(anomalies, scenarioAnomalies...)
+		}
 		results = anomalies
 		if verbose {
 			log.Printf("Detected %d anomalies", len(anomalies))
 		}
+		sendToSinks(sink, anomalies)
 	} else {
 		results = filtered
 	}
@@ -121,19 +231,27 @@ func runLogScan(cmd *cobra.Command, args []string) {
 	}
 }
 
+// readLogFile decodes filename in one pass using the format selected by
+// --format-in (or auto-detected) and returns every entry. The underlying
+// source.Source decodes one line at a time, so this only holds the whole
+// file in memory because the caller wants a finished, non-streaming
+// result (--rules/--sigma/enrichment, or a plain listing); --tail uses the
+// same decoders through runTailLogScan, and the default --anomalies path
+// uses streamLogEntries, to avoid this materialization on large files.
 func readLogFile(filename string) ([]LogEntry, error) {
-	file, err := os.Open(filename)
+	src, err := source.Open(filename, source.Format(formatIn), false)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-	
+	defer src.Close()
+
 	var entries []LogEntry
-	decoder := json.NewDecoder(file)
-	
-	for decoder.More() {
-		var entry LogEntry
-		if err := decoder.Decode(&entry); err != nil {
+	for {
+		entry, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
 			if verbose {
 				log.Printf("Warning: Failed to decode line: %v", err)
 			}
@@ -141,38 +259,45 @@ func readLogFile(filename string) ([]LogEntry, error) {
 		}
 		entries = append(entries, entry)
 	}
-	
+
 	return entries, nil
 }
 
 func applyFilters(entries []LogEntry) []LogEntry {
 	var filtered []LogEntry
-	
+
 	for _, entry := range entries {
-		if userFilter != "" && entry.UserID != userFilter {
-			continue
-		}
-		
-		if ipFilter != "" && entry.IP != ipFilter {
-			continue
+		if matchesFilters(entry) {
+			filtered = append(filtered, entry)
 		}
-		
-		if actionFilter != "" && entry.Action != actionFilter {
-			continue
-		}
-		
-		if timeRange != "" {
-			if !isInTimeRange(entry.Timestamp, timeRange) {
-				continue
-			}
-		}
-		
-		filtered = append(filtered, entry)
 	}
-	
+
 	return filtered
 }
 
+// matchesFilters reports whether entry passes the active --user, --ip,
+// --action and --time-range filters. It's the single-entry primitive both
+// applyFilters (batch) and runTailLogScan (streaming) build on.
+func matchesFilters(entry LogEntry) bool {
+	if userFilter != "" && entry.UserID != userFilter {
+		return false
+	}
+
+	if ipFilter != "" && entry.IP != ipFilter {
+		return false
+	}
+
+	if actionFilter != "" && entry.Action != actionFilter {
+		return false
+	}
+
+	if timeRange != "" && !isInTimeRange(entry.Timestamp, timeRange) {
+		return false
+	}
+
+	return true
+}
+
 func isInTimeRange(timestamp, timeRange string) bool {
 	parts := strings.Split(timeRange, ",")
 	if len(parts) != 2 {
@@ -217,7 +342,14 @@ func detectSecurityAnomalies(entries []LogEntry) []AnomalyResult {
 	// Detect IP-based anomalies
 	ipAnomalies := detectIPAnomalies(entries)
 	anomalies = append(anomalies, ipAnomalies...)
-	
+
+	// Detect impossible-travel, known-malicious-IP, and high-risk-ASN
+	// anomalies (requires --geoip-db / --threat-feed enrichment to have
+	// populated entry.Extra)
+	anomalies = append(anomalies, detectImpossibleTravel(entries)...)
+	anomalies = append(anomalies, detectKnownMaliciousIP(entries)...)
+	anomalies = append(anomalies, detectHighRiskASN(entries)...)
+
 	return anomalies
 }
 
@@ -434,35 +566,54 @@ func formatAsTable(results interface{}) string {
 	// Simple table formatting for anomalies
 	if anomalies, ok := results.([]AnomalyResult); ok {
 		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("%-20s %-10s %-15s %s\n", "TYPE", "COUNT", "TIME WINDOW", "DESCRIPTION"))
-		sb.WriteString(strings.Repeat("-", 80) + "\n")
-		
+		sb.WriteString(fmt.Sprintf("%-20s %-10s %-15s %-12s %-10s %s\n", "TYPE", "COUNT", "TIME WINDOW", "COUNTRY", "ASN", "DESCRIPTION"))
+		sb.WriteString(strings.Repeat("-", 100) + "\n")
+
 		for _, anomaly := range anomalies {
-			sb.WriteString(fmt.Sprintf("%-20s %-10d %-15s %s\n",
-				anomaly.Type, anomaly.Count, anomaly.TimeWindow, anomaly.Description))
+			country, asn := anomalyGeoFields(anomaly)
+			sb.WriteString(fmt.Sprintf("%-20s %-10d %-15s %-12s %-10s %s\n",
+				anomaly.Type, anomaly.Count, anomaly.TimeWindow, country, asn, anomaly.Description))
 		}
-		
+
 		return sb.String()
 	}
-	
+
 	return "Table format not supported for this data type\n"
 }
 
 func formatAsCSV(results interface{}) string {
 	if anomalies, ok := results.([]AnomalyResult); ok {
 		var sb strings.Builder
-		sb.WriteString("Type,Count,TimeWindow,Description,FirstSeen,LastSeen\n")
-		
+		sb.WriteString("Type,Count,TimeWindow,Country,ASN,Description,FirstSeen,LastSeen\n")
+
 		for _, anomaly := range anomalies {
-			sb.WriteString(fmt.Sprintf("%s,%d,%s,\"%s\",%s,%s\n",
-				anomaly.Type, anomaly.Count, anomaly.TimeWindow,
+			country, asn := anomalyGeoFields(anomaly)
+			sb.WriteString(fmt.Sprintf("%s,%d,%s,%s,%s,\"%s\",%s,%s\n",
+				anomaly.Type, anomaly.Count, anomaly.TimeWindow, country, asn,
 				strings.ReplaceAll(anomaly.Description, "\"", "\"\""),
 				anomaly.FirstSeen.Format(time.RFC3339),
 				anomaly.LastSeen.Format(time.RFC3339)))
 		}
-		
+
 		return sb.String()
 	}
-	
+
 	return "CSV format not supported for this data type\n"
-}
\ No newline at end of file
+}
+
+// anomalyGeoFields pulls the --geoip-db-derived country/ASN of an
+// anomaly's first entry out of its Extra/Entries for display in the
+// table/CSV formatters, or ("", "") if --geoip-db enrichment never ran.
+func anomalyGeoFields(anomaly AnomalyResult) (country, asn string) {
+	if len(anomaly.Entries) == 0 {
+		return "", ""
+	}
+	extra := anomaly.Entries[0].Extra
+	if c, ok := extra["geo_country"].(string); ok {
+		country = c
+	}
+	if a, ok := extra["geo_asn"].(uint); ok && a != 0 {
+		asn = fmt.Sprintf("%d", a)
+	}
+	return country, asn
+}
@@ -89,6 +89,87 @@ func createTestLogEntries() []LogEntry {
 			Status:    200,
 			Duration:  2.456,
 		},
+		// Six distinct users sharing one IP, with ipuser6 hitting it twice,
+		// so total entries (7) exceed the distinct-user count (6) -- this
+		// is what distinguishes detectIPAnomalies'/streamDetectIPAnomalies's
+		// Count (total log lines for the IP) from a per-user count.
+		{
+			Timestamp: time.Now().Add(-25*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser1",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.012,
+		},
+		{
+			Timestamp: time.Now().Add(-24*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser2",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.014,
+		},
+		{
+			Timestamp: time.Now().Add(-23*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser3",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.011,
+		},
+		{
+			Timestamp: time.Now().Add(-22*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser4",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.013,
+		},
+		{
+			Timestamp: time.Now().Add(-21*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser5",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.015,
+		},
+		{
+			Timestamp: time.Now().Add(-20*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser6",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.016,
+		},
+		{
+			Timestamp: time.Now().Add(-19*time.Minute).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "Resource accessed",
+			UserID:    "ipuser6",
+			IP:        "10.0.0.50",
+			Action:    "view",
+			Endpoint:  "/api/resource",
+			Status:    200,
+			Duration:  0.017,
+		},
 	}
 }
 
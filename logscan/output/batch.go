@@ -0,0 +1,54 @@
+package output
+
+import "sync"
+
+// batchSink buffers events and only calls through to inner once size
+// events have accumulated, so a sink that talks to a remote service (ES
+// bulk index, Splunk HEC, a webhook) doesn't make one round trip per
+// anomaly. Close flushes whatever is left in the buffer.
+type batchSink struct {
+	inner Sink
+	size  int
+
+	mu  sync.Mutex
+	buf []Event
+}
+
+func withBatch(inner Sink, size int) Sink {
+	if size <= 1 {
+		return inner
+	}
+	return &batchSink{inner: inner, size: size}
+}
+
+func (b *batchSink) Send(events []Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.buf = append(b.buf, events...)
+
+	var firstErr error
+	for len(b.buf) >= b.size {
+		if err := b.inner.Send(b.buf[:b.size]); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		b.buf = b.buf[b.size:]
+	}
+	return firstErr
+}
+
+func (b *batchSink) Close() error {
+	b.mu.Lock()
+	remaining := b.buf
+	b.buf = nil
+	b.mu.Unlock()
+
+	var firstErr error
+	if len(remaining) > 0 {
+		firstErr = b.inner.Send(remaining)
+	}
+	if err := b.inner.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
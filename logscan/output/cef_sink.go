@@ -0,0 +1,130 @@
+package output
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// cefSink writes one CEF or LEEF line per event over UDP/TCP/TLS, the
+// text formats legacy SIEMs (ArcSight, QRadar) expect when they can't
+// consume JSON directly.
+type cefSink struct {
+	network string
+	addr    string
+	tlsCfg  *tls.Config
+	conn    net.Conn
+	leef    bool // true for "leef"/"leef+*" schemes, false for "cef"/"cef+*"
+}
+
+const (
+	cefVendor  = "logscan"
+	cefProduct = "logscan"
+	cefVersion = "1.0"
+)
+
+// newCEFSink builds a Sink from a "cef://" or "leef://" spec (with the
+// same "+tcp"/"+udp"/"+tls" transport suffixes as syslog sinks, defaulting
+// to UDP) and emits CEF or LEEF lines depending on which scheme was used.
+func newCEFSink(u *url.URL) (Sink, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("cef/leef sink requires a host, e.g. cef+tcp://collector:514")
+	}
+
+	leef := strings.HasPrefix(u.Scheme, "leef")
+
+	network := "udp"
+	var tlsCfg *tls.Config
+	switch {
+	case strings.HasSuffix(u.Scheme, "+tcp"):
+		network = "tcp"
+	case strings.HasSuffix(u.Scheme, "+tls"):
+		network = "tcp"
+		tlsCfg = &tls.Config{ServerName: u.Hostname()}
+	}
+
+	return &cefSink{network: network, addr: u.Host, tlsCfg: tlsCfg, leef: leef}, nil
+}
+
+func (s *cefSink) connect() (net.Conn, error) {
+	if s.conn != nil {
+		return s.conn, nil
+	}
+
+	var conn net.Conn
+	var err error
+	if s.tlsCfg != nil {
+		conn, err = tls.Dial(s.network, s.addr, s.tlsCfg)
+	} else {
+		conn, err = net.DialTimeout(s.network, s.addr, 10*time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.conn = conn
+	return conn, nil
+}
+
+func (s *cefSink) Send(events []Event) error {
+	conn, err := s.connect()
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		var line string
+		if s.leef {
+			line = formatLEEF(e)
+		} else {
+			line = formatCEF(e)
+		}
+		if _, err := conn.Write([]byte(line)); err != nil {
+			s.conn = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// formatCEF renders event in ArcSight's Common Event Format:
+// "CEF:Version|Vendor|Product|Version|SignatureID|Name|Severity|Extension".
+func formatCEF(e Event) string {
+	return fmt.Sprintf("CEF:0|%s|%s|%s|%s|%s|5|cnt=%d start=%d end=%d\n",
+		cefVendor, cefProduct, cefVersion,
+		cefEscape(e.Type), cefEscape(e.Description),
+		e.Count, e.FirstSeen.Unix(), e.LastSeen.Unix(),
+	)
+}
+
+// formatLEEF renders event in IBM QRadar's Log Event Extended Format:
+// "LEEF:Version|Vendor|Product|Version|EventID|Extension".
+func formatLEEF(e Event) string {
+	return fmt.Sprintf("LEEF:2.0|%s|%s|%s|%s|cat=%s\tcnt=%d\tstart=%d\tend=%d\tdesc=%s\n",
+		cefVendor, cefProduct, cefVersion,
+		leefEscape(e.Type),
+		leefEscape(e.Type), e.Count, e.FirstSeen.Unix(), e.LastSeen.Unix(), leefEscape(e.Description),
+	)
+}
+
+// cefEscape neutralizes CEF's header field delimiter (|) and its escape
+// character (\) so a detection's free-text Type/Description can't break
+// the header framing.
+func cefEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// leefEscape does the same for LEEF's tab-delimited extension fields.
+func leefEscape(s string) string {
+	return strings.ReplaceAll(s, "\t", " ")
+}
+
+func (s *cefSink) Close() error {
+	if s.conn != nil {
+		return s.conn.Close()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+// Package output sends logscan's detection results to downstream
+// systems: the host's own structured logging, a SIEM, or a generic
+// webhook. A Sink is selected by URL scheme ("es://...", "hec://...",
+// "syslog://...", ...) via New, and --sink may be repeated to fan the
+// same events out to several destinations at once.
+package output
+
+import (
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// Event is one detection result, identical in shape to logscan's
+// AnomalyResult (which is a type alias for Event, the same way LogEntry
+// aliases source.LogEntry) so sinks don't need to know which detector —
+// the legacy thresholds, --rules, or --sigma-rules — produced it.
+type Event struct {
+	Type        string                 `json:"type"`
+	Description string                 `json:"description"`
+	Count       int                    `json:"count"`
+	TimeWindow  string                 `json:"time_window"`
+	FirstSeen   time.Time              `json:"first_seen"`
+	LastSeen    time.Time              `json:"last_seen"`
+	Entries     []source.LogEntry      `json:"entries"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
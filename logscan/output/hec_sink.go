@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// hecSink posts events to a Splunk HTTP Event Collector. HEC accepts a
+// stream of concatenated {"event": ...} JSON objects in one request body,
+// so a whole batch is still a single POST.
+type hecSink struct {
+	endpoint string
+	token    string
+	index    string
+	client   *http.Client
+}
+
+// newHECSink builds a Sink from an "hec://" or "hec+https://" spec such
+// as "hec://<token>@splunk.internal:8088" (the token is the URL
+// userinfo, matching Splunk's own "hec://<token>@host" shorthand).
+// ?index= selects a non-default Splunk index.
+func newHECSink(u *url.URL) (Sink, error) {
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("hec sink requires a token, e.g. hec://<token>@host:8088")
+	}
+
+	scheme := "https"
+	if u.Scheme == "hec" {
+		scheme = "http"
+	}
+
+	return &hecSink{
+		endpoint: fmt.Sprintf("%s://%s/services/collector/event", scheme, u.Host),
+		token:    u.User.Username(),
+		index:    u.Query().Get("index"),
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+func (s *hecSink) Send(events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, e := range events {
+		payload := map[string]interface{}{
+			"time":       float64(e.LastSeen.Unix()),
+			"sourcetype": "logscan:anomaly",
+			"event":      e,
+		}
+		if s.index != "" {
+			payload["index"] = s.index
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.endpoint, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Splunk "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("splunk HEC returned status %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *hecSink) Close() error {
+	return nil
+}
@@ -0,0 +1,38 @@
+package output
+
+import "time"
+
+// retrySink retries a failed Send with exponential backoff before giving
+// up, so a destination that's mid-restart or briefly rate-limiting
+// doesn't lose a batch outright.
+type retrySink struct {
+	inner      Sink
+	maxRetries int
+	baseDelay  time.Duration
+}
+
+func withRetry(inner Sink, maxRetries int, baseDelayMs int) Sink {
+	if maxRetries <= 0 {
+		return inner
+	}
+	return &retrySink{inner: inner, maxRetries: maxRetries, baseDelay: time.Duration(baseDelayMs) * time.Millisecond}
+}
+
+func (r *retrySink) Send(events []Event) error {
+	var err error
+	delay := r.baseDelay
+	for attempt := 0; attempt <= r.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if err = r.inner.Send(events); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (r *retrySink) Close() error {
+	return r.inner.Close()
+}
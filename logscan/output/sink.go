@@ -0,0 +1,132 @@
+package output
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// Sink delivers a batch of Events to one destination. Implementations
+// may reorder or further batch events internally; Send should return
+// promptly once the batch has been handed off (accepted by the remote
+// side, or spooled to disk) rather than streaming one event at a time.
+type Sink interface {
+	Send(events []Event) error
+	Close() error
+}
+
+// MultiSink fans Send out to every configured Sink so --sink can be
+// repeated for fan-out; it returns the first error but still calls every
+// sink, so one bad destination doesn't stop delivery to the rest.
+type MultiSink []Sink
+
+func (m MultiSink) Send(events []Event) error {
+	var firstErr error
+	for _, s := range m {
+		if err := s.Send(events); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m MultiSink) Close() error {
+	var firstErr error
+	for _, s := range m {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Defaults applied unless a sink URL overrides them via query parameters.
+const (
+	defaultBatchSize  = 20
+	defaultRetries    = 5
+	defaultRetryDelay = 500 // milliseconds
+)
+
+// New parses spec (e.g. "es://localhost:9200/logscan-anomalies",
+// "hec://token@splunk.internal:8088", "syslog+tcp://collector:601",
+// "webhook://...", "cef+tcp://collector:514", "slog://stdout") and
+// returns a Sink for it, wrapped with batching, retry-with-backoff, and
+// (if ?spool= is set) a disk spool so a temporarily unreachable
+// destination doesn't drop events.
+//
+// Every scheme accepts three generic query parameters, stripped before
+// the scheme-specific constructor sees the URL: batch (events per
+// flush, default 20), retries (max send attempts, default 5), and spool
+// (a directory to persist events in when every retry is exhausted).
+func New(spec string) (Sink, error) {
+	u, err := url.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --sink %q: %v", spec, err)
+	}
+
+	batchSize, retries, spoolDir, err := popCommonParams(u)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --sink %q: %v", spec, err)
+	}
+
+	inner, err := newScheme(u)
+	if err != nil {
+		return nil, fmt.Errorf("--sink %q: %v", spec, err)
+	}
+
+	sink := withRetry(inner, retries, defaultRetryDelay)
+	if spoolDir != "" {
+		sink = withSpool(sink, spoolDir)
+	}
+	return withBatch(sink, batchSize), nil
+}
+
+func newScheme(u *url.URL) (Sink, error) {
+	switch u.Scheme {
+	case "slog":
+		return newSlogSink(u)
+	case "es", "es+https":
+		return newElasticsearchSink(u)
+	case "hec", "hec+https":
+		return newHECSink(u)
+	case "syslog", "syslog+udp", "syslog+tcp", "syslog+tls":
+		return newSyslogSink(u)
+	case "webhook", "webhook+https", "http", "https":
+		return newWebhookSink(u)
+	case "cef", "cef+tcp", "cef+udp", "cef+tls", "leef", "leef+tcp", "leef+udp", "leef+tls":
+		return newCEFSink(u)
+	default:
+		return nil, fmt.Errorf("unsupported sink scheme %q", u.Scheme)
+	}
+}
+
+// popCommonParams reads and removes the batch/retries/spool query
+// parameters every sink shares, leaving u.RawQuery with only the
+// scheme-specific parameters the individual constructors look at.
+func popCommonParams(u *url.URL) (batchSize, retries int, spoolDir string, err error) {
+	q := u.Query()
+
+	batchSize = defaultBatchSize
+	if v := q.Get("batch"); v != "" {
+		batchSize, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("invalid batch=%q: %v", v, err)
+		}
+		q.Del("batch")
+	}
+
+	retries = defaultRetries
+	if v := q.Get("retries"); v != "" {
+		retries, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, 0, "", fmt.Errorf("invalid retries=%q: %v", v, err)
+		}
+		q.Del("retries")
+	}
+
+	spoolDir = q.Get("spool")
+	q.Del("spool")
+
+	u.RawQuery = q.Encode()
+	return batchSize, retries, spoolDir, nil
+}
@@ -0,0 +1,139 @@
+package output
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func sampleEvents(n int) []Event {
+	events := make([]Event, n)
+	for i := range events {
+		events[i] = Event{Type: "test_anomaly", Description: "test", Count: i + 1, FirstSeen: time.Now(), LastSeen: time.Now()}
+	}
+	return events
+}
+
+func TestNewDispatchesByScheme(t *testing.T) {
+	cases := []string{
+		"slog://stdout",
+		"es://localhost:9200/logscan-anomalies",
+		"hec://token@localhost:8088",
+		"syslog+tcp://localhost:601",
+		"webhook://localhost/hook",
+		"cef+udp://localhost:514",
+		"leef+tcp://localhost:514",
+	}
+
+	for _, spec := range cases {
+		if _, err := New(spec); err != nil {
+			t.Errorf("New(%q) returned error: %v", spec, err)
+		}
+	}
+}
+
+func TestNewRejectsUnknownScheme(t *testing.T) {
+	if _, err := New("ftp://localhost"); err == nil {
+		t.Fatal("expected an error for an unsupported sink scheme")
+	}
+}
+
+func TestNewHECRequiresToken(t *testing.T) {
+	if _, err := New("hec://localhost:8088"); err == nil {
+		t.Fatal("expected an error for a hec:// spec without a token")
+	}
+}
+
+// fakeSink counts how many events it has seen and can be made to fail on
+// the first N Send calls, to exercise batchSink and retrySink.
+type fakeSink struct {
+	failures int
+	batches  [][]Event
+}
+
+func (f *fakeSink) Send(events []Event) error {
+	if f.failures > 0 {
+		f.failures--
+		return errTransient
+	}
+	f.batches = append(f.batches, events)
+	return nil
+}
+
+func (f *fakeSink) Close() error { return nil }
+
+var errTransient = &transientError{}
+
+type transientError struct{}
+
+func (*transientError) Error() string { return "transient failure" }
+
+func TestBatchSinkGroupsBySize(t *testing.T) {
+	fake := &fakeSink{}
+	sink := withBatch(fake, 3)
+
+	if err := sink.Send(sampleEvents(7)); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+	if len(fake.batches) != 2 {
+		t.Fatalf("expected 2 flushed batches of 3, got %d", len(fake.batches))
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close returned error: %v", err)
+	}
+	if len(fake.batches) != 3 {
+		t.Fatalf("expected Close to flush the remaining 1 event as a 3rd batch, got %d", len(fake.batches))
+	}
+	if len(fake.batches[2]) != 1 {
+		t.Fatalf("expected final batch to have 1 event, got %d", len(fake.batches[2]))
+	}
+}
+
+func TestRetrySinkRetriesUntilSuccess(t *testing.T) {
+	fake := &fakeSink{failures: 2}
+	sink := withRetry(fake, 3, 1)
+
+	if err := sink.Send(sampleEvents(1)); err != nil {
+		t.Fatalf("expected Send to eventually succeed, got: %v", err)
+	}
+	if len(fake.batches) != 1 {
+		t.Fatalf("expected exactly 1 successful batch recorded, got %d", len(fake.batches))
+	}
+}
+
+func TestRetrySinkGivesUpAfterMaxRetries(t *testing.T) {
+	fake := &fakeSink{failures: 10}
+	sink := withRetry(fake, 2, 1)
+
+	if err := sink.Send(sampleEvents(1)); err == nil {
+		t.Fatal("expected Send to return an error once retries are exhausted")
+	}
+}
+
+func TestWebhookSinkPostsJSON(t *testing.T) {
+	var received []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			t.Errorf("expected X-Api-Key header to be forwarded, got %q", r.Header.Get("X-Api-Key"))
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := New(server.URL + "?header=X-Api-Key:secret&batch=1")
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(sampleEvents(2)); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if len(received) != 2 {
+		t.Fatalf("expected server to receive 2 events, got %d", len(received))
+	}
+}
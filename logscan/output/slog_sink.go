@@ -0,0 +1,72 @@
+package output
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+)
+
+// slogSink logs one structured record per event through log/slog, so
+// anomalies show up in whatever the host process already uses to collect
+// its own logs (journald, a sidecar log shipper, ...) instead of needing
+// a dedicated SIEM integration.
+type slogSink struct {
+	logger *slog.Logger
+	file   *os.File // non-nil only when the spec points at a path, so Close can release it
+}
+
+// newSlogSink builds a Sink from a "slog://" spec. The host selects the
+// writer: "slog://stdout" and "slog://stderr" log to the process's own
+// streams, anything else is treated as a file path to append to. The
+// handler is JSON by default; ?format=text switches to slog's
+// human-readable TextHandler.
+func newSlogSink(u *url.URL) (Sink, error) {
+	var w *os.File
+	var owned *os.File
+
+	switch u.Host {
+	case "stdout", "":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	default:
+		path := u.Host + u.Path
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open slog sink file %q: %v", path, err)
+		}
+		w, owned = f, f
+	}
+
+	var handler slog.Handler
+	if u.Query().Get("format") == "text" {
+		handler = slog.NewTextHandler(w, nil)
+	} else {
+		handler = slog.NewJSONHandler(w, nil)
+	}
+
+	return &slogSink{logger: slog.New(handler), file: owned}, nil
+}
+
+func (s *slogSink) Send(events []Event) error {
+	for _, e := range events {
+		s.logger.Info("logscan anomaly",
+			"type", e.Type,
+			"description", e.Description,
+			"count", e.Count,
+			"time_window", e.TimeWindow,
+			"first_seen", e.FirstSeen,
+			"last_seen", e.LastSeen,
+			"extra", e.Extra,
+		)
+	}
+	return nil
+}
+
+func (s *slogSink) Close() error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// spoolSink persists a batch to disk instead of losing it when inner
+// (already wrapped in its own retries) is still unreachable, and
+// opportunistically replays whatever is spooled before sending the next
+// batch, so a destination that comes back doesn't need a restart to
+// catch up.
+type spoolSink struct {
+	inner Sink
+	dir   string
+
+	mu  sync.Mutex
+	seq int
+}
+
+func withSpool(inner Sink, dir string) Sink {
+	return &spoolSink{inner: inner, dir: dir}
+}
+
+func (s *spoolSink) Send(events []Event) error {
+	s.replay()
+
+	if err := s.inner.Send(events); err != nil {
+		return s.spool(events)
+	}
+	return nil
+}
+
+// replay attempts to resend every spooled batch, oldest first, deleting
+// each file that sends successfully. A batch that still fails is left in
+// place and replay stops there, since later files are newer and retrying
+// them out of order wouldn't help.
+func (s *spoolSink) replay() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(s.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var events []Event
+		if err := json.Unmarshal(data, &events); err != nil {
+			os.Remove(path)
+			continue
+		}
+		if err := s.inner.Send(events); err != nil {
+			return
+		}
+		os.Remove(path)
+	}
+}
+
+// spool writes events to a new file under dir so they survive a process
+// restart. Filenames are monotonically increasing so replay can recover
+// them in send order.
+func (s *spoolSink) spool(events []Event) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create spool dir %s: %v", s.dir, err)
+	}
+
+	data, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.seq++
+	name := fmt.Sprintf("%d-%04d.json", time.Now().UnixNano(), s.seq)
+	s.mu.Unlock()
+
+	return os.WriteFile(filepath.Join(s.dir, name), data, 0644)
+}
+
+func (s *spoolSink) Close() error {
+	return s.inner.Close()
+}
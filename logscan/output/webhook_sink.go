@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// webhookSink POSTs a batch of events as a JSON array to an arbitrary
+// HTTP endpoint, for destinations that don't have a dedicated sink
+// (internal dashboards, ticketing systems, chatops bots, ...).
+type webhookSink struct {
+	url     string
+	client  *http.Client
+	headers map[string]string
+}
+
+// newWebhookSink builds a Sink from a "webhook://", "http://" or
+// "https://" spec. Auth is supplied via repeatable ?header=Name:Value
+// query parameters (e.g. ?header=Authorization:Bearer+xyz), which are
+// stripped from the URL before the POST and attached as request headers.
+func newWebhookSink(u *url.URL) (Sink, error) {
+	headers := map[string]string{}
+	q := u.Query()
+	for _, h := range q["header"] {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid ?header=%q, expected Name:Value", h)
+		}
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	q.Del("header")
+	u.RawQuery = q.Encode()
+
+	scheme := u.Scheme
+	if scheme == "webhook" {
+		scheme = "https"
+	} else if scheme == "webhook+https" {
+		scheme = "https"
+	}
+	target := *u
+	target.Scheme = scheme
+
+	return &webhookSink{
+		url:     target.String(),
+		client:  &http.Client{Timeout: 30 * time.Second},
+		headers: headers,
+	}, nil
+}
+
+func (s *webhookSink) Send(events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %s", resp.Status)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	return nil
+}
@@ -0,0 +1,224 @@
+// Package rules parses the YAML rule definitions that drive logscan's
+// adaptive (EWMA-based) anomaly detector. A rule says which entries to
+// count, how to group them, and the EWMA parameters used to flag an
+// anomalous bucket, e.g. the equivalent of
+// "action=login status>=400 group_by=user_id window=5m method=ewma k=4":
+//
+//	rules:
+//	  - name: failed_logins
+//	    action: login
+//	    status: ">=400"
+//	    group_by: user_id
+//	    window: 5m
+//	    k: 4
+package rules
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults applied to any field a rule leaves unset.
+const (
+	DefaultAlpha    = 0.1
+	DefaultK        = 3.0
+	DefaultWarmup   = 10
+	DefaultWindow   = time.Minute
+	DefaultCooldown = 15 * time.Minute
+)
+
+// Rule describes one adaptive detection rule.
+type Rule struct {
+	Name string
+
+	// Matching: an entry counts toward this rule only if it satisfies
+	// every condition that's set. Status is a comparison like ">=400".
+	Action string
+	Status string
+
+	GroupBy string        // "user_id" or "ip"
+	Window  time.Duration // bucket width, e.g. 1m, 5m
+
+	Method string  // currently only "ewma"
+	Alpha  float64 // EWMA smoothing factor
+	K      float64 // flag buckets more than k standard deviations above the mean
+	Warmup int     // buckets before a baseline is trusted enough to alert on
+
+	Cooldown time.Duration // suppress repeat alerts for a key within this window
+
+	statusOp  string
+	statusVal int
+}
+
+// rawRule mirrors the YAML schema; Window and Cooldown are parsed with
+// time.ParseDuration separately since yaml.v3 doesn't know how to decode a
+// "5m"-style string straight into a time.Duration.
+type rawRule struct {
+	Name     string  `yaml:"name"`
+	Action   string  `yaml:"action"`
+	Status   string  `yaml:"status"`
+	GroupBy  string  `yaml:"group_by"`
+	Window   string  `yaml:"window"`
+	Method   string  `yaml:"method"`
+	Alpha    float64 `yaml:"alpha"`
+	K        float64 `yaml:"k"`
+	Warmup   int     `yaml:"warmup"`
+	Cooldown string  `yaml:"cooldown"`
+}
+
+// Load reads and parses a YAML rules file, filling in defaults for any
+// field each rule leaves unset.
+func Load(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %v", err)
+	}
+
+	var doc struct {
+		Rules []rawRule `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %v", err)
+	}
+
+	loaded := make([]Rule, 0, len(doc.Rules))
+	for _, raw := range doc.Rules {
+		r, err := raw.toRule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %v", raw.Name, err)
+		}
+		loaded = append(loaded, r)
+	}
+
+	return loaded, nil
+}
+
+func (raw rawRule) toRule() (Rule, error) {
+	r := Rule{
+		Name:    raw.Name,
+		Action:  raw.Action,
+		Status:  raw.Status,
+		GroupBy: raw.GroupBy,
+		Method:  raw.Method,
+		Alpha:   raw.Alpha,
+		K:       raw.K,
+		Warmup:  raw.Warmup,
+	}
+
+	if raw.Window != "" {
+		d, err := time.ParseDuration(raw.Window)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid window %q: %v", raw.Window, err)
+		}
+		r.Window = d
+	}
+	if raw.Cooldown != "" {
+		d, err := time.ParseDuration(raw.Cooldown)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid cooldown %q: %v", raw.Cooldown, err)
+		}
+		r.Cooldown = d
+	}
+
+	applyDefaults(&r)
+	if err := r.compile(); err != nil {
+		return Rule{}, err
+	}
+	return r, nil
+}
+
+func applyDefaults(r *Rule) {
+	if r.Method == "" {
+		r.Method = "ewma"
+	}
+	if r.Alpha == 0 {
+		r.Alpha = DefaultAlpha
+	}
+	if r.K == 0 {
+		r.K = DefaultK
+	}
+	if r.Warmup == 0 {
+		r.Warmup = DefaultWarmup
+	}
+	if r.Window == 0 {
+		r.Window = DefaultWindow
+	}
+	if r.Cooldown == 0 {
+		r.Cooldown = DefaultCooldown
+	}
+	if r.GroupBy == "" {
+		r.GroupBy = "user_id"
+	}
+}
+
+// compile validates the rule and parses Status into the comparison
+// compareStatus evaluates against.
+func (r *Rule) compile() error {
+	if r.Method != "ewma" {
+		return fmt.Errorf("unsupported method %q (want ewma)", r.Method)
+	}
+	if r.GroupBy != "user_id" && r.GroupBy != "ip" {
+		return fmt.Errorf("unsupported group_by %q (want user_id or ip)", r.GroupBy)
+	}
+	if r.Status == "" {
+		return nil
+	}
+
+	op, rest := splitStatusOp(r.Status)
+	val, err := strconv.Atoi(strings.TrimSpace(rest))
+	if err != nil {
+		return fmt.Errorf("invalid status condition %q: %v", r.Status, err)
+	}
+	r.statusOp, r.statusVal = op, val
+	return nil
+}
+
+func splitStatusOp(s string) (op, rest string) {
+	for _, candidate := range []string{">=", "<=", "==", ">", "<", "="} {
+		if strings.HasPrefix(s, candidate) {
+			return candidate, s[len(candidate):]
+		}
+	}
+	return "==", s
+}
+
+// Matches reports whether entry should be counted toward this rule.
+func (r Rule) Matches(entry source.LogEntry) bool {
+	if r.Action != "" && entry.Action != r.Action {
+		return false
+	}
+	if r.statusOp != "" && !compareStatus(entry.Status, r.statusOp, r.statusVal) {
+		return false
+	}
+	return true
+}
+
+func compareStatus(status int, op string, val int) bool {
+	switch op {
+	case ">=":
+		return status >= val
+	case "<=":
+		return status <= val
+	case ">":
+		return status > val
+	case "<":
+		return status < val
+	default:
+		return status == val
+	}
+}
+
+// GroupValue returns the value entries are grouped by for this rule
+// ("" if the rule's GroupBy field isn't present on entry).
+func (r Rule) GroupValue(entry source.LogEntry) string {
+	if r.GroupBy == "ip" {
+		return entry.IP
+	}
+	return entry.UserID
+}
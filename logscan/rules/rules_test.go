@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+func writeRulesFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write rules file: %v", err)
+	}
+	return path
+}
+
+func TestLoadAppliesDefaults(t *testing.T) {
+	path := writeRulesFile(t, `
+rules:
+  - name: failed_logins
+    action: login
+    status: ">=400"
+    group_by: user_id
+    window: 5m
+`)
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("Expected 1 rule, got %d", len(loaded))
+	}
+
+	r := loaded[0]
+	if r.Alpha != DefaultAlpha || r.K != DefaultK || r.Warmup != DefaultWarmup {
+		t.Errorf("Expected default EWMA parameters, got %+v", r)
+	}
+	if r.Window != 5*time.Minute {
+		t.Errorf("Expected 5m window, got %v", r.Window)
+	}
+}
+
+func TestRuleMatches(t *testing.T) {
+	path := writeRulesFile(t, `
+rules:
+  - name: failed_logins
+    action: login
+    status: ">=400"
+    group_by: user_id
+`)
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	r := loaded[0]
+
+	if !r.Matches(source.LogEntry{Action: "login", Status: 401}) {
+		t.Error("Expected rule to match a 401 login")
+	}
+	if r.Matches(source.LogEntry{Action: "login", Status: 200}) {
+		t.Error("Expected rule not to match a 200 login")
+	}
+	if r.Matches(source.LogEntry{Action: "export", Status: 401}) {
+		t.Error("Expected rule not to match a non-login action")
+	}
+}
+
+func TestLoadRejectsUnsupportedMethod(t *testing.T) {
+	path := writeRulesFile(t, `
+rules:
+  - name: bad_rule
+    method: rolling_average
+`)
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected an error for an unsupported method")
+	}
+}
@@ -0,0 +1,221 @@
+package scenario
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// Match is one scenario firing for a single groupby key.
+type Match struct {
+	Scenario    *Scenario
+	GroupValue  string
+	Count       int
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Entries     []source.LogEntry
+}
+
+// Engine evaluates a fixed set of Scenarios against a stream of entries.
+// Like sigma.Engine, it works the same way whether fed a chronologically
+// sorted batch or a live --tail stream: a trigger's window closes (and is
+// checked against its threshold) as soon as a later entry for that
+// scenario+group arrives in the next window, or all at once via FlushAll
+// when the stream ends. A leaky_bucket scenario has no window to close;
+// it's checked, and leaked, on every matching entry instead.
+type Engine struct {
+	scenarios []*Scenario
+	windows   map[string]*triggerWindow
+	buckets   map[string]*leakyBucket
+}
+
+type triggerWindow struct {
+	scenario   *Scenario
+	groupValue string
+	start      time.Time
+	hasWindow  bool
+	count      int
+	distinct   map[string]bool
+	entries    []source.LogEntry
+}
+
+type leakyBucket struct {
+	level    float64
+	lastLeak time.Time
+	entries  []source.LogEntry
+}
+
+// NewEngine returns an Engine evaluating scenarios against every entry
+// fed to it.
+func NewEngine(scenarios []*Scenario) *Engine {
+	return &Engine{
+		scenarios: scenarios,
+		windows:   make(map[string]*triggerWindow),
+		buckets:   make(map[string]*leakyBucket),
+	}
+}
+
+// Feed evaluates entry against every scenario and returns any Matches it
+// produces.
+func (e *Engine) Feed(entry source.LogEntry) []Match {
+	var found []Match
+	for _, s := range e.scenarios {
+		if !s.Matches(entry) {
+			continue
+		}
+		groupValue := s.GroupValue(entry)
+		if groupValue == "" {
+			continue
+		}
+
+		if s.LeakyBucket != nil {
+			if m := e.feedLeakyBucket(s, groupValue, entry); m != nil {
+				found = append(found, *m)
+			}
+			continue
+		}
+		if m := e.feedTrigger(s, groupValue, entry); m != nil {
+			found = append(found, *m)
+		}
+	}
+	return found
+}
+
+// FlushAll closes every still-open trigger window, returning any Matches
+// that produces. Leaky buckets have no equivalent: a bucket below
+// capacity simply stops draining once the stream ends.
+func (e *Engine) FlushAll() []Match {
+	var found []Match
+	for key, w := range e.windows {
+		if m := e.closeWindow(w); m != nil {
+			found = append(found, *m)
+		}
+		delete(e.windows, key)
+	}
+	return found
+}
+
+func (e *Engine) feedTrigger(s *Scenario, groupValue string, entry source.LogEntry) *Match {
+	// Keyed by the *Scenario's identity, not its Name, so two distinct
+	// scenarios that happen to share a name never share a window.
+	key := fmt.Sprintf("%p|%s", s, groupValue)
+
+	hasWindow := s.Trigger.Window > 0
+	var windowStart time.Time
+	if hasWindow {
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			return nil
+		}
+		windowStart = t.Truncate(s.Trigger.Window)
+	}
+
+	w := e.windows[key]
+	var closed *Match
+	if w != nil && hasWindow && !w.start.Equal(windowStart) {
+		closed = e.closeWindow(w)
+		w = nil
+	}
+	if w == nil {
+		w = &triggerWindow{scenario: s, groupValue: groupValue, start: windowStart, hasWindow: hasWindow}
+		if s.Distinct != "" {
+			w.distinct = make(map[string]bool)
+		}
+		e.windows[key] = w
+	}
+
+	w.count++
+	if s.Distinct != "" {
+		if dv, ok := fieldValue(entry, s.Distinct); ok {
+			w.distinct[dv] = true
+		}
+	}
+	w.entries = append(w.entries, entry)
+
+	return closed
+}
+
+func (e *Engine) closeWindow(w *triggerWindow) *Match {
+	count := w.count
+	if w.scenario.Distinct != "" {
+		count = len(w.distinct)
+	}
+	if count < w.scenario.Trigger.Count {
+		return nil
+	}
+
+	start, end := w.start, w.start.Add(w.scenario.Trigger.Window)
+	if !w.hasWindow {
+		start, end = entryTimeRange(w.entries)
+	}
+
+	return &Match{
+		Scenario:    w.scenario,
+		GroupValue:  w.groupValue,
+		Count:       count,
+		WindowStart: start,
+		WindowEnd:   end,
+		Entries:     w.entries,
+	}
+}
+
+// feedLeakyBucket adds one token to groupValue's bucket (after leaking
+// tokens for however long it's been since the last match), and fires
+// once the level exceeds capacity, resetting the bucket afterwards.
+func (e *Engine) feedLeakyBucket(s *Scenario, groupValue string, entry source.LogEntry) *Match {
+	key := fmt.Sprintf("%p|%s", s, groupValue)
+	t, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		return nil
+	}
+
+	b := e.buckets[key]
+	if b == nil {
+		b = &leakyBucket{lastLeak: t}
+		e.buckets[key] = b
+	} else if elapsed := t.Sub(b.lastLeak); elapsed > 0 {
+		b.level -= float64(elapsed) / float64(s.LeakyBucket.LeakSpeed)
+		if b.level < 0 {
+			b.level = 0
+		}
+		b.lastLeak = t
+	}
+
+	b.level++
+	b.entries = append(b.entries, entry)
+	if b.level <= float64(s.LeakyBucket.Capacity) {
+		return nil
+	}
+
+	start, end := entryTimeRange(b.entries)
+	match := &Match{
+		Scenario:    s,
+		GroupValue:  groupValue,
+		Count:       len(b.entries),
+		WindowStart: start,
+		WindowEnd:   end,
+		Entries:     b.entries,
+	}
+	delete(e.buckets, key)
+	return match
+}
+
+// entryTimeRange returns the first and last parseable RFC3339 timestamps
+// in entries, used as a Match's window when it has no fixed trigger
+// window (a leaky_bucket, or a trigger with no "window" set).
+func entryTimeRange(entries []source.LogEntry) (first, last time.Time) {
+	for _, e := range entries {
+		t, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if last.IsZero() || t.After(last) {
+			last = t
+		}
+	}
+	return first, last
+}
@@ -0,0 +1,257 @@
+// Package scenario loads and evaluates CrowdSec-inspired "scenario" YAML
+// detection rules against source.LogEntry values: each scenario is a
+// free-form expr-lang filter expression plus a trigger describing when a
+// matching group of entries counts as an anomaly. A scenario file looks
+// like:
+//
+//	scenarios:
+//	  - name: failed_login_burst
+//	    filter: Action == "login" && Status >= 400
+//	    groupby: UserID
+//	    trigger:
+//	      count: 5
+//	      window: 5m
+//	    labels:
+//	      severity: medium
+//
+//	  - name: ssh_bruteforce
+//	    filter: Action == "ssh_auth_fail"
+//	    groupby: IP
+//	    leaky_bucket:
+//	      capacity: 5
+//	      leak_speed: 10s
+//
+// A trigger fires once groupby's match count reaches count within window
+// (or over the whole input, if window is unset); a leaky_bucket instead
+// fires once a groupby key's bucket level -- one token added per match,
+// draining one token every leak_speed -- overflows capacity, the same
+// brute-force-friendly shape CrowdSec's own scenarios use.
+package scenario
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"gopkg.in/yaml.v3"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// LeakyBucketSpec describes a classic leaky-bucket trigger: Capacity
+// tokens before it overflows, draining one token every LeakSpeed.
+type LeakyBucketSpec struct {
+	Capacity  int           `yaml:"capacity"`
+	LeakSpeed time.Duration `yaml:"-"`
+}
+
+// TriggerSpec fires once Count matches for a groupby key are seen within
+// Window (or ever, if Window is zero).
+type TriggerSpec struct {
+	Count  int           `yaml:"count"`
+	Window time.Duration `yaml:"-"`
+}
+
+// Scenario is one parsed and compiled detection scenario.
+type Scenario struct {
+	Name        string
+	Filter      string
+	GroupBy     []string // one or more LogEntry fields, e.g. ["UserID"] or ["UserID", "IP"]
+	Distinct    string   // if set, a groupby key's count is its number of distinct values of this field, not its raw match count
+	LeakyBucket *LeakyBucketSpec
+	Trigger     *TriggerSpec
+	Labels      map[string]string
+
+	program *vm.Program
+}
+
+// rawScenario mirrors the YAML schema; durations are parsed separately
+// since yaml.v3 doesn't decode "10s"-style strings into time.Duration.
+type rawScenario struct {
+	Name        string `yaml:"name"`
+	Filter      string `yaml:"filter"`
+	GroupBy     string `yaml:"groupby"`
+	Distinct    string `yaml:"distinct"`
+	LeakyBucket *struct {
+		Capacity  int    `yaml:"capacity"`
+		LeakSpeed string `yaml:"leak_speed"`
+	} `yaml:"leaky_bucket"`
+	Trigger *struct {
+		Count  int    `yaml:"count"`
+		Window string `yaml:"window"`
+	} `yaml:"trigger"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// Load reads and compiles a single scenario file.
+func Load(path string) ([]*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario file: %v", err)
+	}
+
+	var doc struct {
+		Scenarios []rawScenario `yaml:"scenarios"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario file: %v", err)
+	}
+
+	scenarios := make([]*Scenario, 0, len(doc.Scenarios))
+	for _, raw := range doc.Scenarios {
+		s, err := raw.toScenario()
+		if err != nil {
+			return nil, fmt.Errorf("scenario %q: %v", raw.Name, err)
+		}
+		scenarios = append(scenarios, s)
+	}
+	return scenarios, nil
+}
+
+// LoadDir loads every *.yml/*.yaml scenario file directly inside dir.
+func LoadDir(dir string) ([]*Scenario, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenarios directory: %v", err)
+	}
+
+	var scenarios []*Scenario
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		loaded, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, loaded...)
+	}
+	return scenarios, nil
+}
+
+// LoadPath loads path as a single scenario file, or as a directory of
+// scenario files if it's a directory.
+func LoadPath(path string) ([]*Scenario, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat scenarios path: %v", err)
+	}
+	if info.IsDir() {
+		return LoadDir(path)
+	}
+	return Load(path)
+}
+
+func (raw rawScenario) toScenario() (*Scenario, error) {
+	if raw.Name == "" {
+		return nil, fmt.Errorf("missing name")
+	}
+	if raw.Filter == "" {
+		return nil, fmt.Errorf("missing filter")
+	}
+	if raw.GroupBy == "" {
+		return nil, fmt.Errorf("missing groupby")
+	}
+	if (raw.LeakyBucket == nil) == (raw.Trigger == nil) {
+		return nil, fmt.Errorf("exactly one of leaky_bucket or trigger must be set")
+	}
+
+	program, err := expr.Compile(raw.Filter, expr.Env(source.LogEntry{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %v", raw.Filter, err)
+	}
+
+	s := &Scenario{
+		Name:     raw.Name,
+		Filter:   raw.Filter,
+		GroupBy:  splitGroupBy(raw.GroupBy),
+		Distinct: raw.Distinct,
+		Labels:   raw.Labels,
+		program:  program,
+	}
+
+	if raw.LeakyBucket != nil {
+		if raw.LeakyBucket.Capacity <= 0 {
+			return nil, fmt.Errorf("leaky_bucket.capacity must be > 0")
+		}
+		leakSpeed, err := time.ParseDuration(raw.LeakyBucket.LeakSpeed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid leaky_bucket.leak_speed %q: %v", raw.LeakyBucket.LeakSpeed, err)
+		}
+		s.LeakyBucket = &LeakyBucketSpec{Capacity: raw.LeakyBucket.Capacity, LeakSpeed: leakSpeed}
+	}
+
+	if raw.Trigger != nil {
+		if raw.Trigger.Count <= 0 {
+			return nil, fmt.Errorf("trigger.count must be > 0")
+		}
+		var window time.Duration
+		if raw.Trigger.Window != "" {
+			window, err = time.ParseDuration(raw.Trigger.Window)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trigger.window %q: %v", raw.Trigger.Window, err)
+			}
+		}
+		s.Trigger = &TriggerSpec{Count: raw.Trigger.Count, Window: window}
+	}
+
+	return s, nil
+}
+
+func splitGroupBy(s string) []string {
+	parts := strings.Split(s, ",")
+	fields := make([]string, len(parts))
+	for i, p := range parts {
+		fields[i] = strings.TrimSpace(p)
+	}
+	return fields
+}
+
+// Matches reports whether entry satisfies s's filter expression.
+func (s *Scenario) Matches(entry source.LogEntry) bool {
+	out, err := expr.Run(s.program, entry)
+	if err != nil {
+		return false
+	}
+	matched, _ := out.(bool)
+	return matched
+}
+
+// GroupValue returns the key entry groups under for s (a "|"-joined
+// tuple for a multi-field groupby), or "" if any field is unset on entry.
+func (s *Scenario) GroupValue(entry source.LogEntry) string {
+	values := make([]string, len(s.GroupBy))
+	for i, field := range s.GroupBy {
+		v, ok := fieldValue(entry, field)
+		if !ok || v == "" {
+			return ""
+		}
+		values[i] = v
+	}
+	return strings.Join(values, "|")
+}
+
+// fieldValue reads one of LogEntry's string fields by name, the same
+// fields Sigma selections and --rules group_by already key off of.
+func fieldValue(entry source.LogEntry, field string) (string, bool) {
+	switch field {
+	case "UserID":
+		return entry.UserID, true
+	case "IP":
+		return entry.IP, true
+	case "Endpoint":
+		return entry.Endpoint, true
+	case "Action":
+		return entry.Action, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,154 @@
+package scenario
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+func writeScenario(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write scenario file: %v", err)
+	}
+	return path
+}
+
+func TestScenarioMatchesFilter(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenario(t, dir, "rule.yml", `
+scenarios:
+  - name: failed_login
+    filter: Action == "login" && Status >= 400
+    groupby: UserID
+    trigger:
+      count: 1
+`)
+	scenarios, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(scenarios) != 1 {
+		t.Fatalf("expected 1 scenario, got %d", len(scenarios))
+	}
+
+	s := scenarios[0]
+	if !s.Matches(source.LogEntry{Action: "login", Status: 403}) {
+		t.Error("expected a 403 login to match")
+	}
+	if s.Matches(source.LogEntry{Action: "login", Status: 200}) {
+		t.Error("expected a 200 login not to match")
+	}
+}
+
+func TestScenarioRejectsMissingTriggerOrLeakyBucket(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenario(t, dir, "rule.yml", `
+scenarios:
+  - name: bad
+    filter: Action == "login"
+    groupby: UserID
+`)
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected an error when neither trigger nor leaky_bucket is set")
+	}
+}
+
+func TestEngineTriggerFiresOnCountWithinWindow(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenario(t, dir, "rule.yml", `
+scenarios:
+  - name: failed_login_burst
+    filter: Action == "login" && Status >= 400
+    groupby: UserID
+    trigger:
+      count: 5
+      window: 5m
+`)
+	scenarios, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	engine := NewEngine(scenarios)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var matches []Match
+	for i := 0; i < 5; i++ {
+		entry := source.LogEntry{
+			UserID:    "alice",
+			Action:    "login",
+			Status:    401,
+			Timestamp: base.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+		}
+		matches = append(matches, engine.Feed(entry)...)
+	}
+	matches = append(matches, engine.FlushAll()...)
+
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].GroupValue != "alice" || matches[0].Count != 5 {
+		t.Errorf("unexpected match: %+v", matches[0])
+	}
+}
+
+func TestEngineLeakyBucketFiresOnOverflow(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenario(t, dir, "rule.yml", `
+scenarios:
+  - name: ssh_bruteforce
+    filter: Action == "ssh_auth_fail"
+    groupby: IP
+    leaky_bucket:
+      capacity: 3
+      leak_speed: 1h
+`)
+	scenarios, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	engine := NewEngine(scenarios)
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var matches []Match
+	for i := 0; i < 4; i++ {
+		entry := source.LogEntry{
+			IP:        "10.0.0.1",
+			Action:    "ssh_auth_fail",
+			Timestamp: base.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+		}
+		matches = append(matches, engine.Feed(entry)...)
+	}
+
+	if len(matches) != 1 {
+		t.Fatalf("expected the 4th fast attempt to overflow a capacity-3 bucket, got %d matches", len(matches))
+	}
+	if matches[0].GroupValue != "10.0.0.1" {
+		t.Errorf("unexpected match: %+v", matches[0])
+	}
+}
+
+func TestScenarioGroupValueTuple(t *testing.T) {
+	dir := t.TempDir()
+	path := writeScenario(t, dir, "rule.yml", `
+scenarios:
+  - name: user_ip_pair
+    filter: "true"
+    groupby: "UserID, IP"
+    trigger:
+      count: 1
+`)
+	scenarios, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	value := scenarios[0].GroupValue(source.LogEntry{UserID: "alice", IP: "10.0.0.1"})
+	if value != "alice|10.0.0.1" {
+		t.Errorf("GroupValue = %q, want %q", value, "alice|10.0.0.1")
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/scenario"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+var scenarioCmd = &cobra.Command{
+	Use:   "scenario",
+	Short: "Work with CrowdSec-style scenario detection rule files",
+}
+
+var scenarioTestCmd = &cobra.Command{
+	Use:   "test <scenario-file-or-dir>",
+	Short: "Validate scenario rules and show which entries in a sample log they'd flag",
+	Long: `test loads the scenario(s) at <scenario-file-or-dir> (a single scenario file, or a
+directory of them), evaluates them against --input, and prints every match, so a
+scenario author can check a scenario's filter/groupby/trigger before shipping it
+alongside --scenarios.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runScenarioTest,
+}
+
+var scenarioTestInput string
+
+func init() {
+	scenarioTestCmd.Flags().StringVarP(&scenarioTestInput, "input", "i", "", "Sample log file to evaluate the scenarios against")
+	scenarioTestCmd.MarkFlagRequired("input")
+
+	scenarioCmd.AddCommand(scenarioTestCmd)
+	rootCmd.AddCommand(scenarioCmd)
+}
+
+func runScenarioTest(cmd *cobra.Command, args []string) error {
+	loaded, err := loadScenarios(args[0])
+	if err != nil {
+		return err
+	}
+
+	src, err := source.Open(scenarioTestInput, source.Format(formatIn), false)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	engine := scenario.NewEngine(loaded)
+	var matches []scenario.Match
+	for {
+		entry, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			continue
+		}
+		matches = append(matches, engine.Feed(entry)...)
+	}
+	matches = append(matches, engine.FlushAll()...)
+
+	fmt.Printf("Loaded %d scenario(s) from %s\n", len(loaded), args[0])
+	if len(matches) == 0 {
+		fmt.Println("No matches against the sample log.")
+		return nil
+	}
+	for _, m := range matches {
+		fmt.Printf("- %s: %s matched %d times (%s .. %s)\n",
+			m.Scenario.Name, m.GroupValue, m.Count, m.WindowStart.Format(time.RFC3339), m.WindowEnd.Format(time.RFC3339))
+	}
+	return nil
+}
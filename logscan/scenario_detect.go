@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/scenario"
+)
+
+// scenarioFeeder adapts a scenario.Engine to the anomalyFeeder interface
+// so --tail can drive it the same way it drives the built-in/adaptive
+// engines.
+type scenarioFeeder struct {
+	engine *scenario.Engine
+}
+
+func newScenarioFeeder(scenarios []*scenario.Scenario) *scenarioFeeder {
+	return &scenarioFeeder{engine: scenario.NewEngine(scenarios)}
+}
+
+func (f *scenarioFeeder) Feed(entry LogEntry) []AnomalyResult {
+	matches := f.engine.Feed(entry)
+	anomalies := make([]AnomalyResult, len(matches))
+	for i, m := range matches {
+		anomalies[i] = scenarioMatchToAnomaly(m)
+	}
+	return anomalies
+}
+
+// loadScenarios loads scenariosPath (a scenario file or a directory of
+// them).
+func loadScenarios(path string) ([]*scenario.Scenario, error) {
+	return scenario.LoadPath(path)
+}
+
+// runScenarioDetection evaluates --scenarios against entries (which must
+// already be sorted chronologically) and returns whatever fired.
+func runScenarioDetection(entries []LogEntry) ([]AnomalyResult, error) {
+	scenarios, err := loadScenarios(scenariosPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]LogEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	engine := scenario.NewEngine(scenarios)
+	var anomalies []AnomalyResult
+	for _, entry := range sorted {
+		for _, m := range engine.Feed(entry) {
+			anomalies = append(anomalies, scenarioMatchToAnomaly(m))
+		}
+	}
+	for _, m := range engine.FlushAll() {
+		anomalies = append(anomalies, scenarioMatchToAnomaly(m))
+	}
+
+	return anomalies, nil
+}
+
+func scenarioMatchToAnomaly(m scenario.Match) AnomalyResult {
+	description := fmt.Sprintf("%s: %s matched %d times", m.Scenario.Name, m.GroupValue, m.Count)
+
+	return AnomalyResult{
+		Type:        m.Scenario.Name,
+		Description: description,
+		Count:       m.Count,
+		TimeWindow:  m.WindowEnd.Sub(m.WindowStart).String(),
+		FirstSeen:   m.WindowStart,
+		LastSeen:    m.WindowEnd,
+		Entries:     limitEntries(m.Entries, 10),
+		Extra: map[string]interface{}{
+			"labels":      m.Scenario.Labels,
+			"group_value": m.GroupValue,
+		},
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRunScenarioDetectionCatchesFailedLoginBurst is chunk3-5's required
+// test: a YAML scenario equivalent to detectFailedLoginBursts's hard-coded
+// "5 failures in 5 minutes" rule must flag the same burst in
+// createTestLogEntries().
+func TestRunScenarioDetectionCatchesFailedLoginBurst(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "failed_login_burst.yml")
+	rule := `
+scenarios:
+  - name: failed_login_burst
+    filter: Action == "login" && Status >= 400
+    groupby: UserID
+    trigger:
+      count: 5
+    labels:
+      severity: medium
+`
+	if err := os.WriteFile(path, []byte(rule), 0644); err != nil {
+		t.Fatalf("failed to write scenario file: %v", err)
+	}
+
+	scenariosPath = dir
+	defer func() { scenariosPath = "" }()
+
+	anomalies, err := runScenarioDetection(createTestLogEntries())
+	if err != nil {
+		t.Fatalf("runScenarioDetection: %v", err)
+	}
+
+	var caught bool
+	for _, a := range anomalies {
+		if a.Type == "failed_login_burst" {
+			caught = true
+			if a.Count < 5 {
+				t.Errorf("expected at least 5 failures in the burst, got %d", a.Count)
+			}
+		}
+	}
+	if !caught {
+		t.Fatalf("expected the failed_login_burst scenario to catch createTestLogEntries()'s burst, got %+v", anomalies)
+	}
+}
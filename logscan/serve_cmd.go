@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/server"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveAddr          string
+	serveAPIKeys       []string
+	serveTLSCert       string
+	serveTLSKey        string
+	serveTLSCA         string
+	serveTLSClientAuth string
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a long-lived HTTP API for remote log ingestion and anomaly querying",
+	Long: `serve starts logscan as a long-running HTTP+JSON API instead of processing
+a single file and exiting:
+
+  POST /logs              accept streamed NDJSON LogEntry values and run
+                          the same --rules/--sigma-rules detection pipeline
+                          --tail uses, one line at a time
+  GET  /anomalies         query anomalies detected so far, with the same
+                          --user/--ip/--action/--time-range filters as the
+                          CLI (as query parameters user/ip/action/time-range)
+  GET  /anomalies/stream  a live Server-Sent Events feed of new detections
+
+Requests are authenticated by a shared --api-key (for bouncers/agents) or
+by mutual TLS once --tls-cert/--tls-key and --tls-client-auth=verify are
+set, mirroring CrowdSec's own bouncer/agent certificate-auth model.`,
+	Run: runServe,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveAddr, "addr", ":8443", "Address to listen on")
+	serveCmd.Flags().StringArrayVar(&serveAPIKeys, "api-key", nil, "Shared secret accepted via the X-Api-Key header (repeatable)")
+	serveCmd.Flags().StringVar(&serveTLSCert, "tls-cert", "", "Server TLS certificate; omit to serve plain HTTP")
+	serveCmd.Flags().StringVar(&serveTLSKey, "tls-key", "", "Server TLS private key")
+	serveCmd.Flags().StringVar(&serveTLSCA, "tls-ca", "", "CA certificate pool used to verify client certs when --tls-client-auth=verify")
+	serveCmd.Flags().StringVar(&serveTLSClientAuth, "tls-client-auth", "", "Client certificate requirement: none, request, require, or verify (default none)")
+
+	// Reuse --rules/--baseline-file/--sigma-rules so the same detection
+	// config works whether logscan is run as a one-shot --tail or as serve.
+	serveCmd.Flags().StringVar(&rulesFile, "rules", "", "YAML rules file of adaptive (EWMA) detection rules; without it, serve uses the built-in fixed thresholds")
+	serveCmd.Flags().StringVar(&baselineFile, "baseline-file", "", "Baseline file (written by the 'baseline' subcommand) to warm-start --rules detection from")
+	serveCmd.Flags().StringVar(&sigmaRulesPath, "sigma-rules", "", "Sigma-style YAML rule file or directory to evaluate alongside the built-in/--rules detectors")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(cmd *cobra.Command, args []string) {
+	engine, err := newTailAnomalyEngine()
+	if err != nil {
+		log.Fatalf("Failed to start anomaly detection: %v", err)
+	}
+
+	srv := server.New(server.Config{
+		Addr:    serveAddr,
+		APIKeys: serveAPIKeys,
+		TLS: server.TLSCfg{
+			CACert:     serveTLSCA,
+			CertFile:   serveTLSCert,
+			KeyFile:    serveTLSKey,
+			ClientAuth: serveTLSClientAuth,
+		},
+	}, engine, server.NewStore())
+
+	if err := srv.Start(); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+
+	select {} // Start runs the listener on its own goroutine; block here forever
+}
@@ -0,0 +1,223 @@
+// Package server implements logscan's long-running `bit serve` mode: an
+// HTTP+JSON API for streaming NDJSON log ingestion and querying the
+// anomalies detected from it, authenticated by API key (for bouncers and
+// agents) or mutual TLS.
+package server
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/output"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// Detector is satisfied by any of logscan's incremental anomaly engines
+// (the built-in fixed thresholds, --rules adaptive detection, or a
+// multiFeeder layering --sigma-rules on top); Server doesn't care which is
+// plugged in, only that it can Feed one entry at a time.
+type Detector interface {
+	Feed(entry source.LogEntry) []output.Event
+}
+
+// Config configures a Server.
+type Config struct {
+	Addr    string
+	APIKeys []string // shared secrets accepted via the X-Api-Key header; empty disables API-key auth (mTLS only)
+	TLS     TLSCfg
+}
+
+// Server is logscan's `bit serve` HTTP API: POST /logs feeds entries into
+// Detector, GET /anomalies queries the Store they produced, and GET
+// /anomalies/stream streams them live over SSE.
+type Server struct {
+	cfg      Config
+	detector Detector
+	store    *Store
+
+	httpServer *http.Server
+	Addr       string // the actual listen address, populated once Start succeeds (useful when Addr is ":0")
+}
+
+func New(cfg Config, detector Detector, store *Store) *Server {
+	return &Server{cfg: cfg, detector: detector, store: store}
+}
+
+// Start binds cfg.Addr (with TLS if configured) and begins serving in the
+// background. It returns once the listener is up, logging the address
+// actually bound -- useful when cfg.Addr ends in ":0".
+func (s *Server) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logs", s.requireAuth(s.handleLogs))
+	mux.HandleFunc("/anomalies", s.requireAuth(s.handleAnomalies))
+	mux.HandleFunc("/anomalies/stream", s.requireAuth(s.handleAnomaliesStream))
+
+	tlsConfig, err := s.cfg.TLS.GetTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	ln, err := net.Listen("tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %v", s.cfg.Addr, err)
+	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+	s.Addr = ln.Addr().String()
+
+	s.httpServer = &http.Server{Handler: mux}
+	log.Printf("logscan serve listening on %s (tls=%v)", s.Addr, tlsConfig != nil)
+
+	go func() {
+		if err := s.httpServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Printf("Warning: serve stopped: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Stop() error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Close()
+}
+
+// requireAuth accepts a request authenticated by a configured API key (the
+// X-Api-Key header) or, when mTLS ClientAuth required a verified client
+// certificate, by TLS alone -- matching the bouncer/agent dual-auth model
+// this was modeled on.
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(s.cfg.APIKeys) == 0 {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) > 0 {
+				// Either neither API keys nor TLS are configured (the
+				// operator chose to run unauthenticated, e.g. behind their
+				// own proxy), or mTLS verified a client certificate.
+				next(w, r)
+				return
+			}
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		key := r.Header.Get("X-Api-Key")
+		for _, want := range s.cfg.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(want)) == 1 {
+				next(w, r)
+				return
+			}
+		}
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	}
+}
+
+// handleLogs accepts POST'd NDJSON LogEntry values, feeding each into
+// Detector and recording whatever anomalies it returns in Store.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+	defer r.Body.Close()
+
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var accepted, detected int
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry source.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			http.Error(w, fmt.Sprintf("invalid NDJSON line: %v", err), http.StatusBadRequest)
+			return
+		}
+		accepted++
+
+		for _, anomaly := range s.detector.Feed(entry) {
+			s.store.Add(anomaly)
+			detected++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]int{"accepted": accepted, "detected": detected})
+}
+
+// handleAnomalies serves GET /anomalies?user=&ip=&action=&time-range=,
+// matching the query parameters --user/--ip/--action/--time-range use on
+// the CLI side.
+func (s *Server) handleAnomalies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "GET required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	events := s.store.List(Query{
+		User:      q.Get("user"),
+		IP:        q.Get("ip"),
+		Action:    q.Get("action"),
+		TimeRange: q.Get("time-range"),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// handleAnomaliesStream serves GET /anomalies/stream as Server-Sent
+// Events: every anomaly Store.Add records from here on is pushed to the
+// client as "data: <json>\n\n" until the client disconnects.
+func (s *Server) handleAnomaliesStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sub := s.store.Subscribe()
+	defer s.store.Unsubscribe(sub)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-time.After(30 * time.Second):
+			// Keep idle connections (and intermediate proxies) alive.
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
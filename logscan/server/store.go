@@ -0,0 +1,130 @@
+package server
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/output"
+)
+
+// storeCapacity bounds how many recent anomalies Store keeps in memory, so
+// a long-running `bit serve` process doesn't grow without limit.
+const storeCapacity = 10000
+
+// Subscriber receives every anomaly Store.Add records, for GET
+// /anomalies/stream.
+type Subscriber chan output.Event
+
+// Store keeps the most recent anomalies detected by a running `bit serve`
+// process, queryable by GET /anomalies and streamable live via GET
+// /anomalies/stream.
+type Store struct {
+	mu          sync.RWMutex
+	events      []output.Event
+	subscribers map[Subscriber]struct{}
+}
+
+func NewStore() *Store {
+	return &Store{subscribers: make(map[Subscriber]struct{})}
+}
+
+// Add records event, evicting the oldest entry once storeCapacity is
+// reached, and fans it out to every live subscriber (non-blocking -- a
+// slow subscriber drops events rather than backpressuring detection).
+func (s *Store) Add(event output.Event) {
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	if len(s.events) > storeCapacity {
+		s.events = s.events[len(s.events)-storeCapacity:]
+	}
+	subs := make([]Subscriber, 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subs = append(subs, sub)
+	}
+	s.mu.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe registers a new live subscriber; the caller must call
+// Unsubscribe when done to stop receiving events.
+func (s *Store) Subscribe() Subscriber {
+	sub := make(Subscriber, 64)
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *Store) Unsubscribe(sub Subscriber) {
+	s.mu.Lock()
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+	close(sub)
+}
+
+// Query mirrors logscan's --user/--ip/--action/--time-range filters, but
+// over the Entries recorded on each stored anomaly: an anomaly matches if
+// any of its Entries satisfies every filter that's set.
+type Query struct {
+	User      string
+	IP        string
+	Action    string
+	TimeRange string // "2006-01-02,2006-01-02", same format as --time-range
+}
+
+// List returns every stored anomaly matching q, oldest first.
+func (s *Store) List(q Query) []output.Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var start, end time.Time
+	hasRange := false
+	if q.TimeRange != "" {
+		if parts := strings.Split(q.TimeRange, ","); len(parts) == 2 {
+			var err1, err2 error
+			start, err1 = time.Parse("2006-01-02", strings.TrimSpace(parts[0]))
+			end, err2 = time.Parse("2006-01-02", strings.TrimSpace(parts[1]))
+			hasRange = err1 == nil && err2 == nil
+		}
+	}
+
+	var matched []output.Event
+	for _, event := range s.events {
+		if matchesQuery(event, q, start, end, hasRange) {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func matchesQuery(event output.Event, q Query, start, end time.Time, hasRange bool) bool {
+	if q.User == "" && q.IP == "" && q.Action == "" && !hasRange {
+		return true
+	}
+	for _, entry := range event.Entries {
+		if q.User != "" && entry.UserID != q.User {
+			continue
+		}
+		if q.IP != "" && entry.IP != q.IP {
+			continue
+		}
+		if q.Action != "" && entry.Action != q.Action {
+			continue
+		}
+		if hasRange {
+			ts, err := time.Parse(time.RFC3339, entry.Timestamp)
+			if err != nil || ts.Before(start) || ts.After(end.Add(24*time.Hour)) {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
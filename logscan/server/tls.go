@@ -0,0 +1,71 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSCfg describes the server's TLS setup, including optional mutual TLS
+// for agent/bouncer authentication, mirroring the certificate-auth pattern
+// CrowdSec's local API uses for its own agent/bouncer connections.
+type TLSCfg struct {
+	CACert     string // PEM file of the CA that signs client certs; required for ClientAuth=="verify"
+	CertFile   string // server certificate
+	KeyFile    string // server private key
+	ClientAuth string // "", "none", "request", "require", or "verify" (require+verify against CACert)
+}
+
+// GetTLSConfig builds a *tls.Config from t, or returns (nil, nil) if
+// CertFile/KeyFile weren't set, meaning the server should listen with
+// plain HTTP.
+func (t TLSCfg) GetTLSConfig() (*tls.Config, error) {
+	if t.CertFile == "" && t.KeyFile == "" {
+		return nil, nil
+	}
+	if t.CertFile == "" || t.KeyFile == "" {
+		return nil, fmt.Errorf("both --tls-cert and --tls-key must be set")
+	}
+
+	cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	switch t.ClientAuth {
+	case "", "none":
+		cfg.ClientAuth = tls.NoClientCert
+	case "request":
+		cfg.ClientAuth = tls.RequestClientCert
+	case "require":
+		cfg.ClientAuth = tls.RequireAnyClientCert
+	case "verify":
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("invalid --tls-client-auth %q (want none, request, require, or verify)", t.ClientAuth)
+	}
+
+	if t.ClientAuth == "verify" && t.CACert == "" {
+		return nil, fmt.Errorf("--tls-client-auth=verify requires --tls-ca")
+	}
+
+	if t.CACert != "" {
+		pem, err := os.ReadFile(t.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --tls-ca: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("--tls-ca %s contains no usable certificates", t.CACert)
+		}
+		cfg.ClientCAs = pool
+	}
+
+	return cfg, nil
+}
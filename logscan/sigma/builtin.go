@@ -0,0 +1,41 @@
+package sigma
+
+import (
+	"embed"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed builtin/*.yml
+var builtinFS embed.FS
+
+// LoadBuiltin returns the rules shipped alongside this package, covering
+// the same detections logscan's hard-coded functions used to: a failed
+// login burst, a data export spike, and an IP accessed by many users.
+// They exist to prove the engine can reproduce those heuristics as
+// ordinary rule files, not to replace --anomalies by default.
+func LoadBuiltin() ([]*Rule, error) {
+	entries, err := builtinFS.ReadDir("builtin")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read built-in rules: %v", err)
+	}
+
+	var rules []*Rule
+	for _, entry := range entries {
+		data, err := builtinFS.ReadFile("builtin/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read built-in rule %q: %v", entry.Name(), err)
+		}
+
+		var r Rule
+		if err := yaml.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse built-in rule %q: %v", entry.Name(), err)
+		}
+		if err := r.compile(); err != nil {
+			return nil, fmt.Errorf("built-in rule %q: %v", r.Title, err)
+		}
+		rules = append(rules, &r)
+	}
+	return rules, nil
+}
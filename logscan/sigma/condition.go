@@ -0,0 +1,241 @@
+package sigma
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// conditionExpr is the parsed form of a rule's condition string: a
+// boolean expression over named selections, plus an optional aggregation
+// clause that turns a boolean match into a "flag once count() crosses a
+// threshold" rule.
+type conditionExpr struct {
+	root boolNode
+	agg  *aggregation
+}
+
+// aggregation is the "| count([field]) by <groupBy> <op> <threshold>
+// [within <window>]" suffix Sigma rules use to express "more than N
+// matches per group". distinctField is empty for a plain count() of
+// matching entries; set, it counts distinct values of that field instead
+// (e.g. count(UserID) by IP, for "how many different users touched this
+// IP").
+type aggregation struct {
+	distinctField string
+	groupBy       string
+	op            string
+	threshold     int
+	window        time.Duration // zero means "over the whole input"
+}
+
+// boolNode is one node of the selection and/or/not tree. It evaluates
+// against r's compiled selections rather than raw Selection values, so a
+// rule's regexes/CIDRs are parsed once at Load time, not once per entry.
+type boolNode interface {
+	eval(entry source.LogEntry, r *Rule) bool
+}
+
+type selectionRef string
+
+func (n selectionRef) eval(entry source.LogEntry, r *Rule) bool {
+	sel, ok := r.compiled[string(n)]
+	return ok && sel.Matches(entry)
+}
+
+type notNode struct{ operand boolNode }
+
+func (n notNode) eval(entry source.LogEntry, r *Rule) bool {
+	return !n.operand.eval(entry, r)
+}
+
+type binaryNode struct {
+	op          string // "and" or "or"
+	left, right boolNode
+}
+
+func (n binaryNode) eval(entry source.LogEntry, r *Rule) bool {
+	if n.op == "and" {
+		return n.left.eval(entry, r) && n.right.eval(entry, r)
+	}
+	return n.left.eval(entry, r) || n.right.eval(entry, r)
+}
+
+var aggPattern = regexp.MustCompile(`^count\((\w*)\)\s*by\s+(\w+)\s*(>=|<=|==|>|<)\s*(\d+)(?:\s+within\s+(\S+))?$`)
+
+// parseCondition parses a condition string like
+// "selection | count() by UserID > 5 within 5m" into its boolean
+// expression and optional aggregation clause.
+func parseCondition(s string) (*conditionExpr, error) {
+	boolPart, aggPart, hasAgg := strings.Cut(s, "|")
+
+	root, err := parseBoolExpr(boolPart)
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &conditionExpr{root: root}
+	if !hasAgg {
+		return expr, nil
+	}
+
+	agg, err := parseAggregation(strings.TrimSpace(aggPart))
+	if err != nil {
+		return nil, err
+	}
+	expr.agg = agg
+	return expr, nil
+}
+
+func parseAggregation(s string) (*aggregation, error) {
+	m := aggPattern.FindStringSubmatch(s)
+	if m == nil {
+		return nil, fmt.Errorf("invalid aggregation clause %q (want \"count([field]) by <field> <op> <N> [within <duration>]\")", s)
+	}
+
+	threshold, err := strconv.Atoi(m[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregation threshold %q: %v", m[4], err)
+	}
+
+	agg := &aggregation{
+		distinctField: m[1],
+		groupBy:       m[2],
+		op:            m[3],
+		threshold:     threshold,
+	}
+	if m[5] != "" {
+		window, err := time.ParseDuration(m[5])
+		if err != nil {
+			return nil, fmt.Errorf("invalid aggregation window %q: %v", m[5], err)
+		}
+		agg.window = window
+	}
+	return agg, nil
+}
+
+func (a *aggregation) crosses(count int) bool {
+	switch a.op {
+	case ">=":
+		return count >= a.threshold
+	case "<=":
+		return count <= a.threshold
+	case "==":
+		return count == a.threshold
+	case "<":
+		return count < a.threshold
+	default: // ">"
+		return count > a.threshold
+	}
+}
+
+// --- boolean expression parser: "selection1 and not (selection2 or selection3)" ---
+
+func parseBoolExpr(s string) (boolNode, error) {
+	tokens := tokenizeBoolExpr(s)
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty condition")
+	}
+	p := &boolParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected token %q in condition", p.tokens[p.pos])
+	}
+	return node, nil
+}
+
+func tokenizeBoolExpr(s string) []string {
+	s = strings.ReplaceAll(s, "(", " ( ")
+	s = strings.ReplaceAll(s, ")", " ) ")
+	return strings.Fields(s)
+}
+
+type boolParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *boolParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *boolParser) next() string {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *boolParser) parseOr() (boolNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *boolParser) parseAnd() (boolNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *boolParser) parseNot() (boolNode, error) {
+	if strings.EqualFold(p.peek(), "not") {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *boolParser) parsePrimary() (boolNode, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of condition")
+	case tok == "(":
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing ')' in condition")
+		}
+		return node, nil
+	case tok == ")":
+		return nil, fmt.Errorf("unexpected ')' in condition")
+	default:
+		return selectionRef(tok), nil
+	}
+}
@@ -0,0 +1,172 @@
+package sigma
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// Match is one rule firing: either a single entry satisfying a
+// condition with no aggregation clause, or a group (e.g. a user ID or
+// IP) whose match count crossed an aggregation clause's threshold.
+type Match struct {
+	Rule        *Rule
+	GroupValue  string
+	Count       int
+	WindowStart time.Time
+	WindowEnd   time.Time
+	Entries     []source.LogEntry
+}
+
+// Engine evaluates a fixed set of rules against a stream of entries. It
+// works the same way whether fed a chronologically sorted batch or a
+// live --tail stream: aggregation buckets close (and are checked against
+// their threshold) as soon as a later entry for that rule+group arrives
+// in the next window, or all at once via FlushAll when the stream ends.
+type Engine struct {
+	rules []*Rule
+	open  map[string]*bucket
+}
+
+type bucket struct {
+	rule       *Rule
+	groupValue string
+	start      time.Time
+	hasWindow  bool
+	count      int
+	distinct   map[string]bool
+	entries    []source.LogEntry
+}
+
+// NewEngine returns an Engine evaluating rules against every entry fed
+// to it.
+func NewEngine(rules []*Rule) *Engine {
+	return &Engine{rules: rules, open: make(map[string]*bucket)}
+}
+
+// Feed evaluates entry against every rule and returns any Matches it
+// produces: an immediate Match for a plain boolean rule, or a Match for
+// any aggregation bucket that closed as a result of entry arriving.
+func (e *Engine) Feed(entry source.LogEntry) []Match {
+	var found []Match
+	for _, r := range e.rules {
+		if !r.Matches(entry) {
+			continue
+		}
+
+		agg := r.condition.agg
+		if agg == nil {
+			t, _ := time.Parse(time.RFC3339, entry.Timestamp)
+			found = append(found, Match{
+				Rule:        r,
+				Count:       1,
+				WindowStart: t,
+				WindowEnd:   t,
+				Entries:     []source.LogEntry{entry},
+			})
+			continue
+		}
+
+		value, ok := fieldValue(entry, agg.groupBy)
+		if !ok || value == "" {
+			continue
+		}
+		// Keyed by the *Rule's identity, not its Title, so two distinct
+		// rules that happen to share a title never share a bucket.
+		key := fmt.Sprintf("%p|%s", r, value)
+
+		hasWindow := agg.window > 0
+		var bucketStart time.Time
+		if hasWindow {
+			t, err := time.Parse(time.RFC3339, entry.Timestamp)
+			if err != nil {
+				continue
+			}
+			bucketStart = t.Truncate(agg.window)
+		}
+
+		b := e.open[key]
+		if b != nil && hasWindow && !b.start.Equal(bucketStart) {
+			if m := e.closeBucket(b); m != nil {
+				found = append(found, *m)
+			}
+			b = nil
+		}
+		if b == nil {
+			b = &bucket{rule: r, groupValue: value, start: bucketStart, hasWindow: hasWindow}
+			if agg.distinctField != "" {
+				b.distinct = make(map[string]bool)
+			}
+			e.open[key] = b
+		}
+
+		b.count++
+		if agg.distinctField != "" {
+			if dv, ok := fieldValue(entry, agg.distinctField); ok {
+				b.distinct[dv] = true
+			}
+		}
+		b.entries = append(b.entries, entry)
+	}
+	return found
+}
+
+// FlushAll closes every still-open aggregation bucket, returning any
+// Matches that produces. Call it once a batch is exhausted; a --tail
+// run has no equivalent end and simply leaves its trailing buckets open.
+func (e *Engine) FlushAll() []Match {
+	var found []Match
+	for key, b := range e.open {
+		if m := e.closeBucket(b); m != nil {
+			found = append(found, *m)
+		}
+		delete(e.open, key)
+	}
+	return found
+}
+
+func (e *Engine) closeBucket(b *bucket) *Match {
+	agg := b.rule.condition.agg
+
+	count := b.count
+	if agg.distinctField != "" {
+		count = len(b.distinct)
+	}
+	if !agg.crosses(count) {
+		return nil
+	}
+
+	start, end := b.start, b.start.Add(agg.window)
+	if !b.hasWindow {
+		start, end = entryTimeRange(b.entries)
+	}
+
+	return &Match{
+		Rule:        b.rule,
+		GroupValue:  b.groupValue,
+		Count:       count,
+		WindowStart: start,
+		WindowEnd:   end,
+		Entries:     b.entries,
+	}
+}
+
+// entryTimeRange returns the first and last parseable RFC3339 timestamps
+// in entries, used as a bucket's window when it has no "within" clause
+// (the bucket spans however much of the input the group appeared in).
+func entryTimeRange(entries []source.LogEntry) (first, last time.Time) {
+	for _, e := range entries {
+		t, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if last.IsZero() || t.After(last) {
+			last = t
+		}
+	}
+	return first, last
+}
@@ -0,0 +1,152 @@
+package sigma
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+	"gopkg.in/yaml.v3"
+)
+
+// UnmarshalYAML splits a detection block into its named selections and
+// its condition string; yaml.v3 can't do this with a plain struct tag
+// since selection names are arbitrary, rule-defined keys.
+func (d *Detection) UnmarshalYAML(value *yaml.Node) error {
+	var raw map[string]yaml.Node
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	d.Selections = make(map[string]Selection)
+	for key, node := range raw {
+		if key == "condition" {
+			if err := node.Decode(&d.Condition); err != nil {
+				return fmt.Errorf("condition: %v", err)
+			}
+			continue
+		}
+
+		var sel Selection
+		if err := node.Decode(&sel); err != nil {
+			return fmt.Errorf("selection %q: %v", key, err)
+		}
+		d.Selections[key] = sel
+	}
+	return nil
+}
+
+// Load reads and compiles a single Sigma-style rule file.
+func Load(path string) (*Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %v", err)
+	}
+
+	var r Rule
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("failed to parse rule file: %v", err)
+	}
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("rule %q: %v", r.Title, err)
+	}
+	return &r, nil
+}
+
+// LoadDir loads every *.yml/*.yaml rule file directly inside dir (the
+// classic Sigma layout of one rule per file).
+func LoadDir(dir string) ([]*Rule, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules directory: %v", err)
+	}
+
+	var rules []*Rule
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext != ".yml" && ext != ".yaml" {
+			continue
+		}
+		r, err := Load(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// LoadPath loads path as a single rule file, or as a directory of rule
+// files if it's a directory.
+func LoadPath(path string) ([]*Rule, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat rules path: %v", err)
+	}
+	if info.IsDir() {
+		return LoadDir(path)
+	}
+	r, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return []*Rule{r}, nil
+}
+
+// compile parses r's condition string and checks it only references
+// selections the rule actually defines.
+func (r *Rule) compile() error {
+	if r.Title == "" {
+		return fmt.Errorf("missing title")
+	}
+	if r.Detection.Condition == "" {
+		return fmt.Errorf("missing detection.condition")
+	}
+
+	expr, err := parseCondition(r.Detection.Condition)
+	if err != nil {
+		return fmt.Errorf("condition %q: %v", r.Detection.Condition, err)
+	}
+	if err := checkSelectionRefs(expr.root, r.Detection.Selections); err != nil {
+		return err
+	}
+
+	r.compiled = make(map[string]compiledSelection, len(r.Detection.Selections))
+	for name, sel := range r.Detection.Selections {
+		compiledSel, err := compileSelection(sel)
+		if err != nil {
+			return fmt.Errorf("selection %q: %v", name, err)
+		}
+		r.compiled[name] = compiledSel
+	}
+
+	r.condition = expr
+	return nil
+}
+
+func checkSelectionRefs(node boolNode, selections map[string]Selection) error {
+	switch n := node.(type) {
+	case selectionRef:
+		if _, ok := selections[string(n)]; !ok {
+			return fmt.Errorf("condition references undefined selection %q", string(n))
+		}
+	case notNode:
+		return checkSelectionRefs(n.operand, selections)
+	case binaryNode:
+		if err := checkSelectionRefs(n.left, selections); err != nil {
+			return err
+		}
+		return checkSelectionRefs(n.right, selections)
+	}
+	return nil
+}
+
+// Matches reports whether entry satisfies r's boolean condition,
+// ignoring any aggregation clause (use an Engine to evaluate
+// aggregation-based rules across a stream of entries).
+func (r *Rule) Matches(entry source.LogEntry) bool {
+	return r.condition.root.eval(entry, r)
+}
@@ -0,0 +1,164 @@
+package sigma
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// fieldValue returns the string form of entry's named field ("Action",
+// "UserID", "IP", ...), matching LogEntry's Go field names. Numeric
+// fields are formatted as decimal text so the same equals/contains/regex
+// matchers work uniformly across string and numeric fields.
+func fieldValue(entry source.LogEntry, field string) (string, bool) {
+	switch field {
+	case "Timestamp":
+		return entry.Timestamp, true
+	case "Level":
+		return entry.Level, true
+	case "Message":
+		return entry.Message, true
+	case "UserID":
+		return entry.UserID, true
+	case "IP":
+		return entry.IP, true
+	case "Action":
+		return entry.Action, true
+	case "Endpoint":
+		return entry.Endpoint, true
+	case "Status":
+		return strconv.Itoa(entry.Status), true
+	case "Duration":
+		return strconv.FormatFloat(entry.Duration, 'f', -1, 64), true
+	default:
+		return "", false
+	}
+}
+
+// Matches reports whether entry satisfies every field matcher in s. It
+// recompiles any regex/cidr matcher on every call, so Rule evaluation
+// (the per-entry hot path during a scan) goes through compiledSelection
+// instead; Matches exists for one-off use, e.g. testing a selection in
+// isolation.
+func (s Selection) Matches(entry source.LogEntry) bool {
+	for field, spec := range s {
+		name, modifier, _ := strings.Cut(field, "|")
+		value, ok := fieldValue(entry, name)
+		if !ok {
+			return false
+		}
+		if !matchField(value, modifier, spec) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchField applies modifier ("", "contains", "regex"/"re", "in", "cidr")
+// to value against spec, the raw YAML value for this field.
+func matchField(value, modifier string, spec interface{}) bool {
+	matcher, err := compileMatcher(modifier, spec)
+	if err != nil {
+		return false
+	}
+	return matcher(value)
+}
+
+// compiledSelection is Selection with every field's matcher compiled
+// once (regexes parsed, CIDRs parsed) instead of on every Matches call,
+// since a Rule is evaluated once per log entry during a scan.
+type compiledSelection map[string]compiledMatcher
+
+type compiledMatcher struct {
+	field   string
+	matches func(value string) bool
+}
+
+// compileSelection compiles every field matcher in s.
+func compileSelection(s Selection) (compiledSelection, error) {
+	compiled := make(compiledSelection, len(s))
+	for field, spec := range s {
+		name, modifier, _ := strings.Cut(field, "|")
+		matcher, err := compileMatcher(modifier, spec)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %v", field, err)
+		}
+		compiled[field] = compiledMatcher{field: name, matches: matcher}
+	}
+	return compiled, nil
+}
+
+// Matches reports whether entry satisfies every compiled field matcher.
+func (s compiledSelection) Matches(entry source.LogEntry) bool {
+	for _, m := range s {
+		value, ok := fieldValue(entry, m.field)
+		if !ok || !m.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+// compileMatcher builds the matcher function for modifier ("", "equals",
+// "contains", "regex"/"re", "in", "cidr") against spec, the raw YAML
+// value for this field, compiling any regex or CIDR once up front.
+func compileMatcher(modifier string, spec interface{}) (func(value string) bool, error) {
+	switch modifier {
+	case "", "equals":
+		if list, ok := toStringList(spec); ok {
+			return func(value string) bool { return containsString(list, value) }, nil
+		}
+		want := fmt.Sprint(spec)
+		return func(value string) bool { return value == want }, nil
+	case "contains":
+		want := fmt.Sprint(spec)
+		return func(value string) bool { return strings.Contains(value, want) }, nil
+	case "regex", "re":
+		re, err := regexp.Compile(fmt.Sprint(spec))
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %v", spec, err)
+		}
+		return re.MatchString, nil
+	case "in":
+		list, _ := toStringList(spec)
+		return func(value string) bool { return containsString(list, value) }, nil
+	case "cidr":
+		_, network, err := net.ParseCIDR(fmt.Sprint(spec))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cidr %q: %v", spec, err)
+		}
+		return func(value string) bool {
+			ip := net.ParseIP(value)
+			return ip != nil && network.Contains(ip)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported field matcher %q", modifier)
+	}
+}
+
+// toStringList converts a YAML scalar or sequence into a string slice; ok
+// is false for a bare scalar (the caller then falls back to equals).
+func toStringList(spec interface{}) ([]string, bool) {
+	items, ok := spec.([]interface{})
+	if !ok {
+		return []string{fmt.Sprint(spec)}, false
+	}
+	list := make([]string, len(items))
+	for i, item := range items {
+		list[i] = fmt.Sprint(item)
+	}
+	return list, true
+}
+
+func containsString(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
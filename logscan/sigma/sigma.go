@@ -0,0 +1,55 @@
+// Package sigma loads and evaluates Sigma-inspired YAML detection rules
+// against source.LogEntry values, so security teams can version-control
+// detection content instead of hard-coding heuristics in Go. A rule looks
+// like:
+//
+//	title: Failed login burst
+//	id: failed_login_burst
+//	logsource:
+//	  category: auth
+//	detection:
+//	  selection:
+//	    Action: login
+//	    Status|regex: '^[45]\d\d$'
+//	  condition: selection | count() by UserID > 5 within 5m
+//	level: medium
+//	tags: [attack.credential_access]
+//
+// detection holds one or more named selections (field matchers, ANDed
+// together within a selection) plus a condition string that combines
+// selections with "and"/"or"/"not" and, optionally, pipes the result into
+// an aggregation clause ("| count() by <field> > N [within <duration>]")
+// that flags a group once its match count crosses the threshold.
+package sigma
+
+// Rule is one parsed Sigma-style detection rule.
+type Rule struct {
+	Title     string    `yaml:"title"`
+	ID        string    `yaml:"id"`
+	LogSource LogSource `yaml:"logsource"`
+	Detection Detection `yaml:"detection"`
+	Level     string    `yaml:"level"`
+	Tags      []string  `yaml:"tags"`
+
+	condition *conditionExpr
+	compiled  map[string]compiledSelection
+}
+
+// LogSource is free-form metadata describing what the rule is meant to
+// apply to; logscan doesn't filter on it today, but it's carried through
+// so rule files stay compatible with the wider Sigma ecosystem.
+type LogSource struct {
+	Category string `yaml:"category"`
+	Product  string `yaml:"product"`
+}
+
+// Detection holds a rule's named selections plus the condition string
+// that combines them.
+type Detection struct {
+	Selections map[string]Selection `yaml:"-"`
+	Condition  string               `yaml:"condition"`
+}
+
+// Selection is a set of field matchers; a LogEntry satisfies a selection
+// only if every matcher in it matches (implicit AND across fields).
+type Selection map[string]interface{}
@@ -0,0 +1,144 @@
+package sigma
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+func writeRule(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write rule file: %v", err)
+	}
+	return path
+}
+
+func TestSelectionMatchers(t *testing.T) {
+	sel := Selection{
+		"Action":       "login",
+		"Status|regex": `^[45]\d\d$`,
+	}
+
+	entry := source.LogEntry{Action: "login", Status: 403}
+	if !sel.Matches(entry) {
+		t.Fatalf("expected selection to match a 403 login")
+	}
+
+	entry.Status = 200
+	if sel.Matches(entry) {
+		t.Fatalf("expected selection not to match a 200 login")
+	}
+}
+
+func TestSelectionCIDRAndIn(t *testing.T) {
+	sel := Selection{
+		"IP":     []interface{}{"1.2.3.4", "5.6.7.8"},
+		"Action": "login",
+	}
+	if !sel.Matches(source.LogEntry{Action: "login", IP: "5.6.7.8"}) {
+		t.Fatalf("expected IP list to match 5.6.7.8")
+	}
+	if sel.Matches(source.LogEntry{Action: "login", IP: "9.9.9.9"}) {
+		t.Fatalf("expected IP list not to match 9.9.9.9")
+	}
+
+	cidrSel := Selection{"IP|cidr": "10.0.0.0/8"}
+	if !cidrSel.Matches(source.LogEntry{IP: "10.1.2.3"}) {
+		t.Fatalf("expected cidr matcher to match an address inside the block")
+	}
+	if cidrSel.Matches(source.LogEntry{IP: "192.168.1.1"}) {
+		t.Fatalf("expected cidr matcher not to match an address outside the block")
+	}
+}
+
+func TestLoadRejectsUndefinedSelection(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRule(t, dir, "bad.yml", `
+title: Bad rule
+detection:
+  selection1:
+    Action: login
+  condition: selection2
+`)
+
+	if _, err := Load(path); err == nil {
+		t.Fatalf("expected an error for a condition referencing an undefined selection")
+	}
+}
+
+func TestEngineWindowedAggregation(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRule(t, dir, "burst.yml", `
+title: Failed login burst
+id: failed_login_burst
+detection:
+  selection:
+    Action: login
+    Status|regex: '^[45]\d\d$'
+  condition: selection | count() by UserID >= 3 within 5m
+`)
+	rule, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	engine := NewEngine([]*Rule{rule})
+	var matches []Match
+	for _, ts := range []string{
+		"2024-01-01T00:00:00Z",
+		"2024-01-01T00:01:00Z",
+		"2024-01-01T00:02:00Z",
+	} {
+		matches = append(matches, engine.Feed(source.LogEntry{
+			Timestamp: ts,
+			Action:    "login",
+			Status:    401,
+			UserID:    "alice",
+		})...)
+	}
+	matches = append(matches, engine.FlushAll()...)
+
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match once the bucket flushed, got %d: %+v", len(matches), matches)
+	}
+	if matches[0].GroupValue != "alice" || matches[0].Count != 3 {
+		t.Fatalf("unexpected match: %+v", matches[0])
+	}
+}
+
+func TestEngineWholeDatasetDistinctCount(t *testing.T) {
+	rule, err := Load(writeRule(t, t.TempDir(), "ip.yml", `
+title: IP multiple users
+detection:
+  selection:
+    IP|regex: '.+'
+  condition: selection | count(UserID) by IP > 2
+`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	engine := NewEngine([]*Rule{rule})
+	for _, user := range []string{"alice", "bob", "carol"} {
+		engine.Feed(source.LogEntry{Timestamp: "2024-01-01T00:00:00Z", IP: "1.2.3.4", UserID: user})
+	}
+
+	matches := engine.FlushAll()
+	if len(matches) != 1 || matches[0].Count != 3 {
+		t.Fatalf("expected 1 match with a distinct count of 3, got %+v", matches)
+	}
+}
+
+func TestLoadBuiltinRulesCompile(t *testing.T) {
+	rules, err := LoadBuiltin()
+	if err != nil {
+		t.Fatalf("LoadBuiltin: %v", err)
+	}
+	if len(rules) != 3 {
+		t.Fatalf("expected 3 built-in rules, got %d", len(rules))
+	}
+}
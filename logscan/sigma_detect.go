@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/sigma"
+)
+
+// sigmaFeeder adapts a sigma.Engine to the anomalyFeeder interface so
+// --tail can drive it the same way it drives the built-in/adaptive
+// engines.
+type sigmaFeeder struct {
+	engine *sigma.Engine
+}
+
+func newSigmaFeeder(rules []*sigma.Rule) *sigmaFeeder {
+	return &sigmaFeeder{engine: sigma.NewEngine(rules)}
+}
+
+func (f *sigmaFeeder) Feed(entry LogEntry) []AnomalyResult {
+	matches := f.engine.Feed(entry)
+	anomalies := make([]AnomalyResult, len(matches))
+	for i, m := range matches {
+		anomalies[i] = sigmaMatchToAnomaly(m)
+	}
+	return anomalies
+}
+
+// multiFeeder fans entry out to several anomalyFeeders and concatenates
+// whatever each one flags, used when --rules (or the built-in
+// thresholds) and --sigma-rules are both active at once.
+type multiFeeder []anomalyFeeder
+
+func (f multiFeeder) Feed(entry LogEntry) []AnomalyResult {
+	var found []AnomalyResult
+	for _, feeder := range f {
+		found = append(found, feeder.Feed(entry)...)
+	}
+	return found
+}
+
+// loadSigmaRules loads sigmaRulesPath (a rule file or a directory of
+// them), falling back to the rules shipped in logscan/sigma/builtin
+// when the path is the literal "builtin".
+func loadSigmaRules(path string) ([]*sigma.Rule, error) {
+	if path == "builtin" {
+		return sigma.LoadBuiltin()
+	}
+	return sigma.LoadPath(path)
+}
+
+// runSigmaDetection evaluates --sigma-rules against entries (which must
+// already be sorted chronologically) and returns whatever fired.
+func runSigmaDetection(entries []LogEntry) ([]AnomalyResult, error) {
+	rules, err := loadSigmaRules(sigmaRulesPath)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := append([]LogEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	engine := sigma.NewEngine(rules)
+	var anomalies []AnomalyResult
+	for _, entry := range sorted {
+		for _, m := range engine.Feed(entry) {
+			anomalies = append(anomalies, sigmaMatchToAnomaly(m))
+		}
+	}
+	for _, m := range engine.FlushAll() {
+		anomalies = append(anomalies, sigmaMatchToAnomaly(m))
+	}
+
+	return anomalies, nil
+}
+
+func sigmaMatchToAnomaly(m sigma.Match) AnomalyResult {
+	anomalyType := m.Rule.ID
+	if anomalyType == "" {
+		anomalyType = m.Rule.Title
+	}
+
+	description := m.Rule.Title
+	if m.GroupValue != "" {
+		description = fmt.Sprintf("%s: %s matched %d times", m.Rule.Title, m.GroupValue, m.Count)
+	}
+
+	return AnomalyResult{
+		Type:        anomalyType,
+		Description: description,
+		Count:       m.Count,
+		TimeWindow:  m.WindowEnd.Sub(m.WindowStart).String(),
+		FirstSeen:   m.WindowStart,
+		LastSeen:    m.WindowEnd,
+		Entries:     limitEntries(m.Entries, 10),
+		Extra: map[string]interface{}{
+			"rule_id":     m.Rule.ID,
+			"level":       m.Rule.Level,
+			"tags":        m.Rule.Tags,
+			"group_value": m.GroupValue,
+		},
+	}
+}
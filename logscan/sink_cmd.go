@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/output"
+)
+
+// buildSinks constructs the fan-out destination for --sink, or nil if it
+// wasn't set. A single output.MultiSink is used even for one spec so
+// callers don't need to special-case the count.
+func buildSinks() (output.Sink, error) {
+	if len(sinkSpecs) == 0 {
+		return nil, nil
+	}
+
+	sinks := make(output.MultiSink, 0, len(sinkSpecs))
+	for _, spec := range sinkSpecs {
+		sink, err := output.New(spec)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// sendToSinks forwards anomalies to sink, if one is configured. Delivery
+// errors are logged but never fatal, the same as backup-cli's notify
+// hooks, so a broken SIEM destination can't take down a detection run.
+func sendToSinks(sink output.Sink, anomalies []AnomalyResult) {
+	if sink == nil || len(anomalies) == 0 {
+		return
+	}
+	if err := sink.Send(anomalies); err != nil {
+		log.Printf("Warning: failed to send anomalies to --sink: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// clfLinePattern matches Common and Combined Log Format lines, e.g.:
+//
+//	127.0.0.1 - frank [10/Oct/2023:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+var clfLinePattern = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+)[^"]*" (\d{3}) (\S+)`)
+
+// clfSource decodes Common/Combined Log Format access log lines.
+type clfSource struct {
+	lines *lineReader
+}
+
+func (s *clfSource) Next() (LogEntry, error) {
+	line, err := s.lines.ReadLine()
+	if err != nil {
+		return LogEntry{}, err
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return s.Next()
+	}
+	return parseCLFLine(string(line))
+}
+
+func parseCLFLine(line string) (LogEntry, error) {
+	m := clfLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return LogEntry{}, fmt.Errorf("failed to parse CLF line: %q", line)
+	}
+
+	status, _ := strconv.Atoi(m[6])
+
+	timestamp := m[3]
+	if ts, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[3]); err == nil {
+		timestamp = ts.Format(time.RFC3339)
+	}
+
+	level := "INFO"
+	if status >= 400 {
+		level = "ERROR"
+	}
+
+	return LogEntry{
+		Timestamp: timestamp,
+		Level:     level,
+		Message:   fmt.Sprintf("%s %s", m[4], m[5]),
+		UserID:    m[2],
+		IP:        m[1],
+		Action:    m[4],
+		Endpoint:  m[5],
+		Status:    status,
+	}, nil
+}
+
+func (s *clfSource) Close() error {
+	return s.lines.Close()
+}
@@ -0,0 +1,47 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// decompress wraps r in a gzip or zstd reader when the magic bytes or
+// path's extension indicate compression; otherwise it returns r unchanged.
+// The returned bool reports whether decompression was applied, since a
+// compressed source can't be cheaply reopened mid-stream on rotation (see
+// lineReader.waitForMore).
+func decompress(r io.Reader, path string) (io.Reader, bool, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(4)
+	if err != nil && err != io.EOF {
+		return nil, false, err
+	}
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic), strings.HasSuffix(path, ".gz"):
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to open gzip stream: %v", err)
+		}
+		return gz, true, nil
+	case bytes.Equal(magic, zstdMagic), strings.HasSuffix(path, ".zst"):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to open zstd stream: %v", err)
+		}
+		return zr.IOReadCloser(), true, nil
+	default:
+		return br, false, nil
+	}
+}
@@ -0,0 +1,32 @@
+package source
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// jsonlSource decodes one JSON object per non-empty line.
+type jsonlSource struct {
+	lines *lineReader
+}
+
+func (s *jsonlSource) Next() (LogEntry, error) {
+	line, err := s.lines.ReadLine()
+	if err != nil {
+		return LogEntry{}, err
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return s.Next()
+	}
+
+	var entry LogEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return LogEntry{}, fmt.Errorf("failed to decode JSON line: %v", err)
+	}
+	return entry, nil
+}
+
+func (s *jsonlSource) Close() error {
+	return s.lines.Close()
+}
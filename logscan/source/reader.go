@@ -0,0 +1,93 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"time"
+)
+
+// tailPollInterval is how often a tailing lineReader checks for newly
+// appended data once it has caught up to EOF.
+const tailPollInterval = 500 * time.Millisecond
+
+// lineReader reads newline-delimited records from a (possibly
+// decompressed) stream. When tail is true, reaching EOF blocks and polls
+// for more data instead of returning io.EOF; if the file shrinks in the
+// meantime it's treated as having been rotated and is reopened from the
+// start. tail is forced off for compressed sources, since reopening a
+// gzip/zstd stream mid-read would desync the decompressor.
+type lineReader struct {
+	br      *bufio.Reader
+	file    *os.File
+	path    string
+	tail    bool
+	pos     int64
+	pending []byte // bytes read past the last complete line while tailing
+}
+
+func newLineReader(br *bufio.Reader, file *os.File, path string, tail bool) *lineReader {
+	return &lineReader{br: br, file: file, path: path, tail: tail}
+}
+
+// ReadLine returns the next line with its trailing newline stripped. It
+// returns io.EOF once the stream is exhausted, unless the reader is
+// tailing, in which case it blocks until more data (completing a new line)
+// arrives instead of returning a half-written one.
+func (r *lineReader) ReadLine() ([]byte, error) {
+	for {
+		line, err := r.br.ReadBytes('\n')
+		if err == nil {
+			if len(r.pending) > 0 {
+				line = append(r.pending, line...)
+				r.pending = nil
+			}
+			r.pos += int64(len(line))
+			return bytes.TrimRight(line, "\r\n"), nil
+		}
+		if err != io.EOF {
+			return nil, err
+		}
+		if !r.tail {
+			if len(line) == 0 && len(r.pending) == 0 {
+				return nil, io.EOF
+			}
+			full := append(r.pending, line...)
+			r.pending = nil
+			r.pos += int64(len(full))
+			return bytes.TrimRight(full, "\r\n"), nil
+		}
+		// Tailing: stash the partial line and wait for the rest to be
+		// appended rather than handing callers a truncated record.
+		if len(line) > 0 {
+			r.pending = append(r.pending, line...)
+		}
+		r.waitForMore()
+	}
+}
+
+// waitForMore sleeps briefly and, if the file shrank since our last read
+// (log rotation), reopens it from the start.
+func (r *lineReader) waitForMore() {
+	time.Sleep(tailPollInterval)
+
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return // file missing momentarily (mid-rotation); retry next tick
+	}
+	if info.Size() < r.pos {
+		f, err := os.Open(r.path)
+		if err != nil {
+			return
+		}
+		r.file.Close()
+		r.file = f
+		r.br = bufio.NewReader(f)
+		r.pos = 0
+	}
+}
+
+func (r *lineReader) Close() error {
+	return r.file.Close()
+}
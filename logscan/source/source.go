@@ -0,0 +1,126 @@
+// Package source provides streaming decoders for the log formats logscan
+// can analyze. Each Source yields one LogEntry at a time instead of
+// materializing the whole file, so logscan's filters and anomaly detectors
+// can run in O(window) memory rather than O(file) on multi-gigabyte logs.
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LogEntry is the canonical parsed representation of one log line,
+// regardless of which on-disk format it was decoded from. Fields that a
+// format doesn't carry (e.g. UserID for a syslog line) are left zero.
+type LogEntry struct {
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Message   string                 `json:"message"`
+	UserID    string                 `json:"user_id"`
+	IP        string                 `json:"ip"`
+	Action    string                 `json:"action"`
+	Endpoint  string                 `json:"endpoint"`
+	Status    int                    `json:"status"`
+	Duration  float64                `json:"duration"`
+	Extra     map[string]interface{} `json:"extra"`
+}
+
+// Source yields decoded log entries one at a time. Next returns io.EOF once
+// the underlying file is exhausted. A Source opened with tail=true instead
+// blocks in Next and waits for more data to be appended, like `tail -f`.
+type Source interface {
+	Next() (LogEntry, error)
+	Close() error
+}
+
+// Format identifies an on-disk log layout.
+type Format string
+
+const (
+	FormatJSONLines Format = "jsonl"
+	FormatSyslog    Format = "syslog"
+	FormatCLF       Format = "clf"
+)
+
+// clfSniffPattern is used only for content-based auto-detection; the real
+// parser in clf.go is more permissive about the request line.
+var clfSniffPattern = regexp.MustCompile(`^\S+ \S+ \S+ \[[^\]]+\] "`)
+
+// Open opens path and returns a Source that decodes it as format. If format
+// is empty, the format is auto-detected from the file extension and,
+// failing that, from the first line of (decompressed) content. Gzip and
+// zstd compression are detected from magic bytes or a .gz/.zst extension
+// and transparently decompressed regardless of format.
+//
+// If tail is true, the returned Source keeps path open past EOF and yields
+// newly appended lines instead of returning io.EOF; a shrinking file size
+// is treated as log rotation and the file is reopened from the top.
+func Open(path string, format Format, tail bool) (Source, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r, compressed, err := decompress(file, path)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	br := bufio.NewReader(r)
+
+	if format == "" {
+		format, err = detectFormat(path, br)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	lines := newLineReader(br, file, path, tail && !compressed)
+
+	switch format {
+	case FormatJSONLines:
+		return &jsonlSource{lines: lines}, nil
+	case FormatSyslog:
+		return &syslogSource{lines: lines}, nil
+	case FormatCLF:
+		return &clfSource{lines: lines}, nil
+	default:
+		file.Close()
+		return nil, fmt.Errorf("unsupported --format-in %q (want jsonl, syslog, or clf)", format)
+	}
+}
+
+// detectFormat guesses a Format from path's extension first (stripping any
+// compression suffix), falling back to sniffing the first line of br
+// without consuming it.
+func detectFormat(path string, br *bufio.Reader) (Format, error) {
+	base := strings.TrimSuffix(strings.TrimSuffix(path, ".gz"), ".zst")
+	switch {
+	case strings.HasSuffix(base, ".jsonl"), strings.HasSuffix(base, ".json"), strings.HasSuffix(base, ".ndjson"):
+		return FormatJSONLines, nil
+	case strings.HasSuffix(base, ".clf"), strings.HasSuffix(base, ".access"):
+		return FormatCLF, nil
+	case strings.HasSuffix(base, ".log"), strings.HasSuffix(base, ".syslog"):
+		return FormatSyslog, nil
+	}
+
+	peek, err := br.Peek(512)
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("failed to sniff log format: %v", err)
+	}
+	trimmed := bytes.TrimSpace(peek)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		return FormatJSONLines, nil
+	case clfSniffPattern.Match(trimmed):
+		return FormatCLF, nil
+	default:
+		return FormatSyslog, nil
+	}
+}
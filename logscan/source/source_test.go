@@ -0,0 +1,105 @@
+package source
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write temp file: %v", err)
+	}
+	return path
+}
+
+func TestOpenJSONLines(t *testing.T) {
+	path := writeTempFile(t, "entries.jsonl", `{"user_id":"alice","action":"login","status":200}
+{"user_id":"bob","action":"export","status":200}
+`)
+
+	src, err := Open(path, "", false)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer src.Close()
+
+	var got []LogEntry
+	for {
+		entry, err := src.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next failed: %v", err)
+		}
+		got = append(got, entry)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(got))
+	}
+	if got[0].UserID != "alice" || got[1].UserID != "bob" {
+		t.Errorf("Unexpected entries: %+v", got)
+	}
+}
+
+func TestOpenCLF(t *testing.T) {
+	path := writeTempFile(t, "access.clf", `127.0.0.1 - frank [10/Oct/2023:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 404 2326`+"\n")
+
+	src, err := Open(path, "", false)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer src.Close()
+
+	entry, err := src.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if entry.IP != "127.0.0.1" || entry.Endpoint != "/apache_pb.gif" || entry.Status != 404 {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+	if entry.Level != "ERROR" {
+		t.Errorf("Expected ERROR level for 4xx status, got %s", entry.Level)
+	}
+}
+
+func TestOpenSyslog(t *testing.T) {
+	path := writeTempFile(t, "app.syslog", "Jan  2 15:04:05 myhost sshd[1234]: authentication failure for root\n")
+
+	src, err := Open(path, "", false)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer src.Close()
+
+	entry, err := src.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if entry.Action != "sshd" || entry.Level != "ERROR" {
+		t.Errorf("Unexpected entry: %+v", entry)
+	}
+}
+
+func TestOpenExplicitFormatOverridesDetection(t *testing.T) {
+	path := writeTempFile(t, "entries.txt", `{"user_id":"alice"}`+"\n")
+
+	src, err := Open(path, FormatJSONLines, false)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer src.Close()
+
+	entry, err := src.Next()
+	if err != nil {
+		t.Fatalf("Next failed: %v", err)
+	}
+	if entry.UserID != "alice" {
+		t.Errorf("Expected UserID 'alice', got %q", entry.UserID)
+	}
+}
@@ -0,0 +1,83 @@
+package source
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syslogLinePattern matches classic RFC 3164 syslog lines, e.g.:
+//
+//	Jan  2 15:04:05 host process[1234]: message text
+var syslogLinePattern = regexp.MustCompile(`^(\w{3}\s+\d{1,2}\s+\d{2}:\d{2}:\d{2})\s+(\S+)\s+([^:\[\s]+)(?:\[(\d+)\])?:\s*(.*)$`)
+
+// syslogSource decodes RFC 3164-style syslog lines. RFC 3164 has no
+// structured severity field, so Level is derived from common keywords in
+// the message text.
+type syslogSource struct {
+	lines *lineReader
+	year  int // syslog timestamps carry no year; assume the current one
+}
+
+func (s *syslogSource) Next() (LogEntry, error) {
+	line, err := s.lines.ReadLine()
+	if err != nil {
+		return LogEntry{}, err
+	}
+	if len(bytes.TrimSpace(line)) == 0 {
+		return s.Next()
+	}
+	return s.parseLine(string(line))
+}
+
+func (s *syslogSource) parseLine(line string) (LogEntry, error) {
+	m := syslogLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return LogEntry{}, fmt.Errorf("failed to parse syslog line: %q", line)
+	}
+
+	if s.year == 0 {
+		s.year = time.Now().Year()
+	}
+
+	timestamp := line
+	if ts, err := time.Parse("Jan _2 15:04:05 2006", fmt.Sprintf("%s %d", m[1], s.year)); err == nil {
+		timestamp = ts.Format(time.RFC3339)
+	}
+
+	var extra map[string]interface{}
+	if m[4] != "" {
+		if pid, err := strconv.Atoi(m[4]); err == nil {
+			extra = map[string]interface{}{"pid": pid, "host": m[2]}
+		}
+	} else {
+		extra = map[string]interface{}{"host": m[2]}
+	}
+
+	return LogEntry{
+		Timestamp: timestamp,
+		Level:     severityFromMessage(m[5]),
+		Message:   m[5],
+		Action:    m[3],
+		Extra:     extra,
+	}, nil
+}
+
+func severityFromMessage(msg string) string {
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.Contains(lower, "error"), strings.Contains(lower, "fail"):
+		return "ERROR"
+	case strings.Contains(lower, "warn"):
+		return "WARN"
+	default:
+		return "INFO"
+	}
+}
+
+func (s *syslogSource) Close() error {
+	return s.lines.Close()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/baseline"
+)
+
+// Tuning for detectStatisticalAnomalies's per-key baselines: alpha≈0.05
+// gives a slow-moving baseline (roughly a 20-bucket memory), and k=4
+// flags a bucket once it's more than 4 MADs above what's typical for that
+// key, matching the fixed-threshold detectors' rough false-positive rate
+// without a hard-coded cutoff.
+const (
+	statisticalWindow = 5 * time.Minute
+	statisticalAlpha  = 0.05
+	statisticalK      = 4.0
+	statisticalWarmup = 10
+)
+
+// detectStatisticalAnomalies replaces detectFailedLoginBursts and
+// detectSuspiciousAPIAccess's fixed cutoffs (5 failures in 5 minutes, 120
+// calls in an hour) with a baseline that adapts per user and per endpoint:
+// it buckets matching entries into statisticalWindow windows and flags a
+// bucket once its count exceeds that key's EWMA+k·MAD baseline, learned
+// and persisted in store across runs.
+func detectStatisticalAnomalies(entries []LogEntry, store *baseline.MADStore) []AnomalyResult {
+	var anomalies []AnomalyResult
+
+	anomalies = append(anomalies, statisticalBuckets(entries, store, "user_rate",
+		func(e LogEntry) string {
+			if e.Action == "login" && e.Status >= 400 {
+				return e.UserID
+			}
+			return ""
+		})...)
+
+	anomalies = append(anomalies, statisticalBuckets(entries, store, "endpoint_rate",
+		func(e LogEntry) string { return e.Endpoint })...)
+
+	return anomalies
+}
+
+// statisticalBuckets groups entries into statisticalWindow buckets keyed
+// by groupBy(entry) (an empty result excludes the entry), then runs each
+// bucket's final count through label+"|"+groupValue's MADTracker as soon
+// as the bucket closes -- the same close-on-next-window shape
+// adaptiveEngine.Feed uses for --rules, just over a plain batch instead of
+// a live stream.
+func statisticalBuckets(entries []LogEntry, store *baseline.MADStore, label string, groupBy func(LogEntry) string) []AnomalyResult {
+	type bucket struct {
+		start   time.Time
+		count   int
+		entries []LogEntry
+	}
+
+	sorted := append([]LogEntry(nil), entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	var anomalies []AnomalyResult
+	open := make(map[string]*bucket)
+
+	closeBucket := func(groupValue string, b *bucket) {
+		tracker := store.Tracker(label + "|" + groupValue)
+		baselineEWMA, baselineMAD := tracker.EWMA, tracker.MAD
+		anomalous, deviation := tracker.Observe(float64(b.count), statisticalAlpha, statisticalK, statisticalWarmup)
+		if !anomalous {
+			return
+		}
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "statistical_" + label,
+			Description: fmt.Sprintf("%s %q had %d events in %s (%.2f MADs above baseline of %.2f)", label, groupValue, b.count, statisticalWindow, deviation, baselineEWMA),
+			Count:       b.count,
+			TimeWindow:  statisticalWindow.String(),
+			FirstSeen:   b.start,
+			LastSeen:    b.start.Add(statisticalWindow),
+			Entries:     limitEntries(b.entries, 10),
+			Extra: map[string]interface{}{
+				"baseline_ewma": baselineEWMA,
+				"baseline_mad":  baselineMAD,
+				"deviation":     deviation,
+			},
+		})
+	}
+
+	for _, entry := range sorted {
+		groupValue := groupBy(entry)
+		if groupValue == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		bucketStart := t.Truncate(statisticalWindow)
+
+		b := open[groupValue]
+		if b != nil && !b.start.Equal(bucketStart) {
+			closeBucket(groupValue, b)
+			b = nil
+		}
+		if b == nil {
+			b = &bucket{start: bucketStart}
+			open[groupValue] = b
+		}
+		b.count++
+		b.entries = append(b.entries, entry)
+	}
+	for groupValue, b := range open {
+		closeBucket(groupValue, b)
+	}
+
+	return anomalies
+}
+
+// runStatisticalDetection loads --stat-baseline (or starts an empty
+// MADStore if it doesn't exist yet), runs detectStatisticalAnomalies, and
+// saves the updated baselines back so the next run keeps learning from
+// where this one left off.
+func runStatisticalDetection(entries []LogEntry) ([]AnomalyResult, error) {
+	store, err := baseline.LoadMADStore(statBaselineFile)
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies := detectStatisticalAnomalies(entries, store)
+
+	if err := store.Save(statBaselineFile); err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
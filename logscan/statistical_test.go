@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/baseline"
+)
+
+// failedLoginBucket generates n failed-login entries for user spaced a
+// few seconds apart, all inside the statisticalWindow bucket starting at
+// bucketStart.
+func failedLoginBucket(user string, bucketStart time.Time, n int) []LogEntry {
+	entries := make([]LogEntry, n)
+	for i := range entries {
+		entries[i] = LogEntry{
+			Timestamp: bucketStart.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+			UserID:    user,
+			Action:    "login",
+			Status:    401,
+		}
+	}
+	return entries
+}
+
+// TestDetectStatisticalAnomaliesFlagsSpikeNotOrdinaryVariation is
+// chunk3-4's required test: seed a baseline of ordinary variation around
+// ~5 failed logins per window, then assert a later spike is flagged while
+// none of the seeding buckets were.
+func TestDetectStatisticalAnomaliesFlagsSpikeNotOrdinaryVariation(t *testing.T) {
+	store := baseline.NewMADStore()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	counts := []int{5, 4, 6, 5, 7, 4, 5, 6, 5, 4, 6, 5}
+	for i, n := range counts {
+		bucketStart := base.Add(time.Duration(i) * statisticalWindow)
+		anomalies := detectStatisticalAnomalies(failedLoginBucket("alice", bucketStart, n), store)
+		for _, a := range anomalies {
+			t.Errorf("bucket %d (%d failures): ordinary variation around a baseline of ~5 should not be flagged, got %+v", i, n, a)
+		}
+	}
+
+	spikeStart := base.Add(time.Duration(len(counts)) * statisticalWindow)
+	anomalies := detectStatisticalAnomalies(failedLoginBucket("alice", spikeStart, 50), store)
+	if len(anomalies) != 1 {
+		t.Fatalf("expected the 50-failure spike to be flagged, got %d anomalies: %+v", len(anomalies), anomalies)
+	}
+	if anomalies[0].Type != "statistical_user_rate" {
+		t.Errorf("anomalies[0].Type = %q, want %q", anomalies[0].Type, "statistical_user_rate")
+	}
+}
+
+// TestDetectStatisticalAnomaliesPersistsAcrossRuns exercises the
+// --stat-baseline checkpoint file runStatisticalDetection reads/writes,
+// confirming a baseline learned in one run is still in effect after a
+// reload, the same guarantee --baseline-file gives the --rules detector.
+func TestDetectStatisticalAnomaliesPersistsAcrossRuns(t *testing.T) {
+	statBaselineFile = t.TempDir() + "/stat-baseline.json"
+	defer func() { statBaselineFile = "" }()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 12; i++ {
+		bucketStart := base.Add(time.Duration(i) * statisticalWindow)
+		entries := failedLoginBucket("bob", bucketStart, 5)
+		if _, err := runStatisticalDetection(entries); err != nil {
+			t.Fatalf("seeding run %d: %v", i, err)
+		}
+	}
+
+	spikeStart := base.Add(12 * statisticalWindow)
+	anomalies, err := runStatisticalDetection(failedLoginBucket("bob", spikeStart, 50))
+	if err != nil {
+		t.Fatalf("spike run: %v", err)
+	}
+	if len(anomalies) != 1 {
+		t.Fatalf("expected the reloaded baseline to flag the spike, got %d anomalies", len(anomalies))
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/baseline"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/rules"
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// anomalyFeeder is implemented by both streamAnomalyEngine (the built-in
+// fixed thresholds) and adaptiveEngine (--rules), so runTailLogScan can
+// drive either one entry at a time without caring which is active.
+type anomalyFeeder interface {
+	Feed(entry LogEntry) []AnomalyResult
+}
+
+// newTailAnomalyEngine picks the fixed-threshold or --rules adaptive
+// engine (matching runLogScan's batch-mode choice in
+// runAdaptiveDetection), then layers --sigma-rules and --scenarios on top
+// of it if set, the same way the batch path adds runSigmaDetection's and
+// runScenarioDetection's results.
+func newTailAnomalyEngine() (anomalyFeeder, error) {
+	var engine anomalyFeeder
+	if rulesFile == "" {
+		engine = newStreamAnomalyEngine()
+	} else {
+		ruleSet, err := rules.Load(rulesFile)
+		if err != nil {
+			return nil, err
+		}
+
+		store := baseline.NewStore()
+		if baselineFile != "" {
+			store, err = baseline.LoadStore(baselineFile)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		engine = newAdaptiveEngine(ruleSet, store)
+	}
+
+	if sigmaRulesPath != "" {
+		sigmaRules, err := loadSigmaRules(sigmaRulesPath)
+		if err != nil {
+			return nil, err
+		}
+		engine = multiFeeder{engine, newSigmaFeeder(sigmaRules)}
+	}
+
+	if scenariosPath != "" {
+		scenarios, err := loadScenarios(scenariosPath)
+		if err != nil {
+			return nil, err
+		}
+		engine = multiFeeder{engine, newScenarioFeeder(scenarios)}
+	}
+
+	return engine, nil
+}
+
+// runTailLogScan keeps inputFile open and processes newly appended lines
+// as they arrive, like `tail -f`. Entries flow through a channel into
+// matchesFilters and then, with --anomalies, into an anomalyFeeder whose
+// per-key state is windowed rather than file-sized, so a long-running
+// --tail session uses O(window) memory no matter how large the log grows.
+func runTailLogScan() {
+	src, err := source.Open(inputFile, source.Format(formatIn), true)
+	if err != nil {
+		log.Fatalf("Failed to open log file: %v", err)
+	}
+	defer src.Close()
+
+	if verbose {
+		log.Printf("Tailing log file: %s", inputFile)
+	}
+
+	entries := make(chan LogEntry, 64)
+	go func() {
+		defer close(entries)
+		for {
+			entry, err := src.Next()
+			if err != nil {
+				if verbose {
+					log.Printf("Warning: Failed to decode line: %v", err)
+				}
+				continue
+			}
+			entries <- entry
+		}
+	}()
+
+	engine, err := newTailAnomalyEngine()
+	if err != nil {
+		log.Fatalf("Failed to start anomaly detection: %v", err)
+	}
+
+	sink, err := buildSinks()
+	if err != nil {
+		log.Fatalf("Failed to set up --sink: %v", err)
+	}
+	if sink != nil {
+		defer sink.Close()
+	}
+
+	enricher, err := buildEnricher()
+	if err != nil {
+		log.Fatalf("Failed to set up GeoIP/threat-intel enrichment: %v", err)
+	}
+	if enricher != nil {
+		defer enricher.Close()
+	}
+
+	for entry := range entries {
+		if enricher != nil {
+			enricher.Enrich(&entry)
+		}
+
+		if !matchesFilters(entry) {
+			continue
+		}
+
+		if detectAnomalies {
+			anomalies := engine.Feed(entry)
+			for _, anomaly := range anomalies {
+				printTailResult(anomaly)
+			}
+			sendToSinks(sink, anomalies)
+			continue
+		}
+
+		printTailResult(entry)
+	}
+}
+
+// printTailResult writes v to stdout as a single JSON line so --tail output
+// can be piped into tools like jq as it's produced.
+func printTailResult(v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Println(string(data))
+}
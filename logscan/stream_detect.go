@@ -0,0 +1,305 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bilalabbasid/Business-Intelligence-Tool/logscan/source"
+)
+
+// streamLogEntries opens filename with the active --format-in decoder and
+// emits each decoded LogEntry on the returned channel as it's read, so a
+// caller never has to hold the whole file in memory the way readLogFile's
+// []LogEntry result does. It's the building block behind
+// detectSecurityAnomaliesStreaming.
+func streamLogEntries(filename string) (<-chan LogEntry, error) {
+	src, err := source.Open(filename, source.Format(formatIn), false)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan LogEntry, 256)
+	go func() {
+		defer close(out)
+		defer src.Close()
+		for {
+			entry, err := src.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				if verbose {
+					log.Printf("Warning: Failed to decode line: %v", err)
+				}
+				continue
+			}
+			out <- entry
+		}
+	}()
+	return out, nil
+}
+
+// detectSecurityAnomaliesStreaming detects the same four anomaly classes as
+// detectSecurityAnomalies, but as a fan-out of one goroutine per detector,
+// each fed its own copy of in and holding only the bounded per-key window
+// state documented on streamAnomalyEngine (ring-buffer-style trimming via
+// trimOlderThan, 5-minute buckets for login bursts). That keeps a multi-GB
+// input file's memory footprint at O(window) instead of O(file size), and
+// lets the four detectors run concurrently instead of four sequential
+// passes over the full entry set.
+func detectSecurityAnomaliesStreaming(in <-chan LogEntry) []AnomalyResult {
+	detectors := []func(<-chan LogEntry) []AnomalyResult{
+		streamDetectFailedLoginBursts,
+		streamDetectDataExportSpikes,
+		streamDetectSuspiciousAPIAccess,
+		streamDetectIPAnomalies,
+	}
+
+	branches := make([]chan LogEntry, len(detectors))
+	for i := range branches {
+		branches[i] = make(chan LogEntry, 256)
+	}
+
+	go func() {
+		defer func() {
+			for _, b := range branches {
+				close(b)
+			}
+		}()
+		for entry := range in {
+			for _, b := range branches {
+				b <- entry
+			}
+		}
+	}()
+
+	results := make([][]AnomalyResult, len(detectors))
+	var wg sync.WaitGroup
+	for i, detect := range detectors {
+		wg.Add(1)
+		go func(i int, detect func(<-chan LogEntry) []AnomalyResult, branch <-chan LogEntry) {
+			defer wg.Done()
+			results[i] = detect(branch)
+		}(i, detect, branches[i])
+	}
+	wg.Wait()
+
+	var anomalies []AnomalyResult
+	for _, r := range results {
+		anomalies = append(anomalies, r...)
+	}
+	return anomalies
+}
+
+// streamDetectFailedLoginBursts is detectFailedLoginBursts's incremental
+// counterpart: it keeps only each user's failed logins inside a 5-minute
+// bucket (plus one stale bucket of slack before eviction) instead of every
+// failed login in the file. A bucket is only reported once it's finalized —
+// evicted because a later entry moved the window past it, or because the
+// stream ended — so, unlike a first-crossing report, Count and Entries
+// always reflect the bucket's full and final contents, matching batch.
+func streamDetectFailedLoginBursts(in <-chan LogEntry) []AnomalyResult {
+	loginFailures := make(map[string]map[string][]LogEntry)
+	var anomalies []AnomalyResult
+
+	finalizeBucket := func(userID, bucket string, failures []LogEntry) {
+		if len(failures) < 5 {
+			return
+		}
+		bucketTime, _ := time.Parse(time.RFC3339, bucket)
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "failed_login_burst",
+			Description: fmt.Sprintf("User %s had %d failed login attempts in 5 minutes", userID, len(failures)),
+			Count:       len(failures),
+			TimeWindow:  "5 minutes",
+			FirstSeen:   bucketTime,
+			LastSeen:    bucketTime.Add(loginBurstWindow),
+			Entries:     append([]LogEntry(nil), failures...),
+		})
+	}
+
+	for entry := range in {
+		if entry.Action != "login" || entry.Status < 400 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+
+		bucketTime := t.Truncate(loginBurstWindow)
+		bucket := bucketTime.Format(time.RFC3339)
+
+		if loginFailures[entry.UserID] == nil {
+			loginFailures[entry.UserID] = make(map[string][]LogEntry)
+		}
+		loginFailures[entry.UserID][bucket] = append(loginFailures[entry.UserID][bucket], entry)
+
+		for b, failures := range loginFailures[entry.UserID] {
+			if bt, err := time.Parse(time.RFC3339, b); err == nil && t.Sub(bt) > 2*loginBurstWindow {
+				finalizeBucket(entry.UserID, b, failures)
+				delete(loginFailures[entry.UserID], b)
+			}
+		}
+	}
+
+	for userID, buckets := range loginFailures {
+		for bucket, failures := range buckets {
+			finalizeBucket(userID, bucket, failures)
+		}
+	}
+	return anomalies
+}
+
+// streamDetectDataExportSpikes is detectDataExportSpikes's incremental
+// counterpart, keeping only each user's exports inside exportWindow. It
+// reports each user's final, post-trim export count once the stream ends,
+// rather than freezing Count at whichever entry first crossed the
+// threshold, so it matches batch's full-group total.
+func streamDetectDataExportSpikes(in <-chan LogEntry) []AnomalyResult {
+	exports := make(map[string][]LogEntry)
+
+	for entry := range in {
+		if !isExportEntry(entry) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		exports[entry.UserID] = trimOlderThan(append(exports[entry.UserID], entry), t, exportWindow)
+	}
+
+	var anomalies []AnomalyResult
+	for userID, userExports := range exports {
+		if len(userExports) <= 10 {
+			continue
+		}
+
+		first, _ := time.Parse(time.RFC3339, userExports[0].Timestamp)
+		last, _ := time.Parse(time.RFC3339, userExports[len(userExports)-1].Timestamp)
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "data_export_spike",
+			Description: fmt.Sprintf("User %s performed %d data exports", userID, len(userExports)),
+			Count:       len(userExports),
+			TimeWindow:  fmt.Sprintf("%.1f hours", last.Sub(first).Hours()),
+			FirstSeen:   first,
+			LastSeen:    last,
+			Entries:     limitEntries(userExports, 10),
+		})
+	}
+	return anomalies
+}
+
+// streamDetectSuspiciousAPIAccess is detectSuspiciousAPIAccess's incremental
+// counterpart, keeping only each user's requests inside apiAccessWindow. It
+// reports each user's final, post-trim request count once the stream ends,
+// rather than freezing Count at whichever entry first crossed the
+// threshold, so it matches batch's full-group total.
+func streamDetectSuspiciousAPIAccess(in <-chan LogEntry) []AnomalyResult {
+	requests := make(map[string][]LogEntry)
+
+	for entry := range in {
+		if entry.Endpoint == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		requests[entry.UserID] = trimOlderThan(append(requests[entry.UserID], entry), t, apiAccessWindow)
+	}
+
+	var anomalies []AnomalyResult
+	for userID, userRequests := range requests {
+		if len(userRequests) <= 100 {
+			continue
+		}
+
+		first, _ := time.Parse(time.RFC3339, userRequests[0].Timestamp)
+		last, _ := time.Parse(time.RFC3339, userRequests[len(userRequests)-1].Timestamp)
+		duration := last.Sub(first)
+		if duration.Minutes() >= 60 {
+			continue
+		}
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "suspicious_api_access",
+			Description: fmt.Sprintf("User %s made %d API requests in %.1f minutes", userID, len(userRequests), duration.Minutes()),
+			Count:       len(userRequests),
+			TimeWindow:  fmt.Sprintf("%.1f minutes", duration.Minutes()),
+			FirstSeen:   first,
+			LastSeen:    last,
+			Entries:     limitEntries(userRequests, 10),
+		})
+	}
+	return anomalies
+}
+
+// streamDetectIPAnomalies is detectIPAnomalies's incremental counterpart,
+// keeping only each IP's entries inside ipAnomalyWindow. It reports each
+// IP's final, post-trim entry set once the stream ends, rather than
+// freezing Count at whichever entry first crossed the threshold, so it
+// matches batch's full-group total: Count and Entries cover every log line
+// seen for the IP, not one entry per distinct user.
+func streamDetectIPAnomalies(in <-chan LogEntry) []AnomalyResult {
+	ipEntries := make(map[string][]LogEntry)
+
+	for entry := range in {
+		if entry.IP == "" || entry.UserID == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, entry.Timestamp)
+		if err != nil {
+			continue
+		}
+		ipEntries[entry.IP] = trimOlderThan(append(ipEntries[entry.IP], entry), t, ipAnomalyWindow)
+	}
+
+	var anomalies []AnomalyResult
+	for ip, entries := range ipEntries {
+		users := make(map[string]bool)
+		for _, e := range entries {
+			users[e.UserID] = true
+		}
+		if len(users) <= 5 {
+			continue
+		}
+
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Timestamp < entries[j].Timestamp })
+
+		var userList []string
+		for user := range users {
+			userList = append(userList, user)
+		}
+		sort.Strings(userList)
+
+		first, _ := time.Parse(time.RFC3339, entries[0].Timestamp)
+		last, _ := time.Parse(time.RFC3339, entries[len(entries)-1].Timestamp)
+
+		anomalies = append(anomalies, AnomalyResult{
+			Type:        "ip_multiple_users",
+			Description: fmt.Sprintf("IP %s accessed by %d different users: %s", ip, len(users), strings.Join(userList, ", ")),
+			Count:       len(entries),
+			TimeWindow:  fmt.Sprintf("%.1f hours", last.Sub(first).Hours()),
+			FirstSeen:   first,
+			LastSeen:    last,
+			Entries:     limitEntries(entries, 10),
+		})
+	}
+	return anomalies
+}
+
+// isExportEntry reports whether entry looks like a data-export action,
+// shared by the batch, --tail, and streaming data-export-spike detectors.
+func isExportEntry(entry LogEntry) bool {
+	return strings.Contains(strings.ToLower(entry.Action), "export") ||
+		strings.Contains(strings.ToLower(entry.Endpoint), "export") ||
+		strings.Contains(strings.ToLower(entry.Message), "export")
+}
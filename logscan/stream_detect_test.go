@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+// feedStreaming pushes entries onto a channel and runs
+// detectSecurityAnomaliesStreaming over it, the streaming-pipeline
+// equivalent of calling detectSecurityAnomalies(entries) directly.
+func feedStreaming(entries []LogEntry) []AnomalyResult {
+	ch := make(chan LogEntry, len(entries))
+	for _, e := range entries {
+		ch <- e
+	}
+	close(ch)
+	return detectSecurityAnomaliesStreaming(ch)
+}
+
+// sortAnomalies orders anomalies deterministically so batch and streaming
+// results (built from map iteration, which Go doesn't order) can be
+// compared directly.
+func sortAnomalies(anomalies []AnomalyResult) []AnomalyResult {
+	sorted := append([]AnomalyResult(nil), anomalies...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Type != sorted[j].Type {
+			return sorted[i].Type < sorted[j].Type
+		}
+		return sorted[i].Description < sorted[j].Description
+	})
+	return sorted
+}
+
+// TestDetectSecurityAnomaliesStreamingMatchesBatch is chunk3-1's required
+// correctness test: the fan-out streaming pipeline must find exactly the
+// same anomalies as the existing batch detectSecurityAnomalies, just
+// without materializing the input.
+func TestDetectSecurityAnomaliesStreamingMatchesBatch(t *testing.T) {
+	testEntries := createTestLogEntries()
+
+	batch := sortAnomalies(detectSecurityAnomalies(testEntries))
+	streamed := sortAnomalies(feedStreaming(testEntries))
+
+	if len(batch) != len(streamed) {
+		t.Fatalf("streaming found %d anomalies, batch found %d", len(streamed), len(batch))
+	}
+	for i := range batch {
+		if batch[i].Type != streamed[i].Type || batch[i].Description != streamed[i].Description || batch[i].Count != streamed[i].Count {
+			t.Errorf("anomaly %d mismatch:\n batch:    %+v\n streamed: %+v", i, batch[i], streamed[i])
+		}
+		if len(batch[i].Entries) != len(streamed[i].Entries) {
+			t.Errorf("anomaly %d Entries length mismatch: batch has %d, streamed has %d", i, len(batch[i].Entries), len(streamed[i].Entries))
+		}
+	}
+}
+
+// TestStreamDetectIPAnomaliesMatchesBatch is the regression test for the
+// bug a code review caught: createTestLogEntries() now includes an IP
+// shared by six distinct users (ipuser6 hitting it twice), which crosses
+// the >5-user threshold while giving the IP more entries than users. A
+// streaming detector that counted one entry per user instead of per log
+// line would report Count=6 here where batch reports Count=7.
+func TestStreamDetectIPAnomaliesMatchesBatch(t *testing.T) {
+	testEntries := createTestLogEntries()
+
+	var batchIP *AnomalyResult
+	for _, a := range detectIPAnomalies(testEntries) {
+		if a.Type == "ip_multiple_users" {
+			a := a
+			batchIP = &a
+		}
+	}
+	if batchIP == nil {
+		t.Fatalf("test fixture invalid: expected batch to report an ip_multiple_users anomaly")
+	}
+
+	ch := make(chan LogEntry, len(testEntries))
+	for _, e := range testEntries {
+		ch <- e
+	}
+	close(ch)
+
+	var streamedIP *AnomalyResult
+	for _, a := range streamDetectIPAnomalies(ch) {
+		if a.Type == "ip_multiple_users" {
+			a := a
+			streamedIP = &a
+		}
+	}
+	if streamedIP == nil {
+		t.Fatalf("streaming detector did not report the ip_multiple_users anomaly batch found: %+v", batchIP)
+	}
+
+	if streamedIP.Count != batchIP.Count {
+		t.Errorf("streaming Count diverged from batch: batch=%d streamed=%d", batchIP.Count, streamedIP.Count)
+	}
+	if len(streamedIP.Entries) != len(batchIP.Entries) {
+		t.Errorf("streaming Entries length diverged from batch: batch=%d streamed=%d", len(batchIP.Entries), len(streamedIP.Entries))
+	}
+}
+
+// TestStreamDetectFailedLoginBurstsOverThreshold pins the divergence a code
+// review caught: createTestLogEntries() only ever puts exactly 5 failures
+// in a bucket, so a streaming detector that freezes Count at the first
+// threshold crossing passes by coincidence. This fixture uses fixed,
+// same-bucket timestamps and a burst well past the threshold, so a frozen
+// Count (5) would visibly diverge from batch's full-group total (8).
+func TestStreamDetectFailedLoginBurstsOverThreshold(t *testing.T) {
+	bucket := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	var entries []LogEntry
+	for i := 0; i < 8; i++ {
+		entries = append(entries, LogEntry{
+			Timestamp: bucket.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+			Level:     "ERROR",
+			Message:   "User login failed",
+			UserID:    "burstuser",
+			IP:        "10.0.0.1",
+			Action:    "login",
+			Endpoint:  "/api/auth/login",
+			Status:    401,
+		})
+	}
+
+	batch := detectFailedLoginBursts(entries)
+	if len(batch) != 1 || batch[0].Count != 8 {
+		t.Fatalf("test fixture invalid: expected batch to report a single burst with Count=8, got %+v", batch)
+	}
+
+	ch := make(chan LogEntry, len(entries))
+	for _, e := range entries {
+		ch <- e
+	}
+	close(ch)
+	streamed := streamDetectFailedLoginBursts(ch)
+
+	if len(streamed) != 1 || streamed[0].Count != batch[0].Count {
+		t.Errorf("streaming detector diverged from batch: want Count=%d, got %+v", batch[0].Count, streamed)
+	}
+}
+
+// syntheticLogEntries generates n entries across a handful of users and IPs,
+// spaced a second apart, without ever holding all of them at once -- it
+// sends each one to out as it's generated, the same access pattern
+// streamLogEntries gives the real detectors.
+func syntheticLogEntries(n int, out chan<- LogEntry) {
+	defer close(out)
+	base := time.Now().Add(-time.Duration(n) * time.Second)
+	for i := 0; i < n; i++ {
+		user := fmt.Sprintf("user%d", i%50)
+		ip := fmt.Sprintf("10.0.%d.%d", (i/256)%256, i%256)
+		status := 200
+		action := "view"
+		if i%37 == 0 {
+			status = 401
+			action = "login"
+		}
+		out <- LogEntry{
+			Timestamp: base.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+			Level:     "INFO",
+			Message:   "synthetic",
+			UserID:    user,
+			IP:        ip,
+			Action:    action,
+			Endpoint:  "/api/resource",
+			Status:    status,
+			Duration:  0.01,
+		}
+	}
+}
+
+// BenchmarkDetectSecurityAnomaliesStreaming1M is chunk3-1's required
+// benchmark: it runs the fan-out pipeline over a synthetic 1M-entry stream
+// that is never materialized into a slice, demonstrating the per-detector
+// ring-buffer state (bounded by loginBurstWindow/exportWindow/
+// apiAccessWindow/ipAnomalyWindow) keeps memory constant regardless of
+// input size, unlike the old readLogFile+detectSecurityAnomalies path this
+// chunk replaces for the default --anomalies case.
+func BenchmarkDetectSecurityAnomaliesStreaming1M(b *testing.B) {
+	const n = 1_000_000
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ch := make(chan LogEntry, 256)
+		go syntheticLogEntries(n, ch)
+		detectSecurityAnomaliesStreaming(ch)
+	}
+}